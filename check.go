@@ -0,0 +1,105 @@
+package glue
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// CheckError is the error returned by `Check`, it lists every problem found
+// between two struct types.
+type CheckError struct {
+	Dst, Src reflect.Type
+	Errs     []error
+}
+
+func (e *CheckError) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf(
+		"%v: %d problem(s) gluing %v into %v: %s",
+		ErrGlue, len(e.Errs), e.Src, e.Dst, strings.Join(msgs, "; "),
+	)
+}
+
+// Is reports whether any of the problems matches `target`.
+func (e *CheckError) Is(target error) bool {
+	for _, err := range e.Errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return target == ErrGlue
+}
+
+// Unwrap returns the problems found.
+func (e *CheckError) Unwrap() []error {
+	return e.Errs
+}
+
+// Check builds the plan of gluing src into dst without copying any data and
+// reports every problem as a `*CheckError`, it is meant to catch broken
+// mappings on startup or in unit tests.
+// Invalid tags, ambiguous promoted fields, missing converters and required
+// fields without counterpart are always reported, other fields having no
+// counterpart are only reported with `DoStrict`.
+// The parameters are pointers to struct as `Glue` takes, or nil pointers of
+// the struct types.
+func Check(dst, src interface{}, opts ...GlueOption) error {
+	var (
+		tdst = reflect.TypeOf(dst)
+		tsrc = reflect.TypeOf(src)
+		errs []error
+	)
+	if !isPtrToStructType(tdst) || !isPtrToStructType(tsrc) {
+		return ErrNotPtrToStruct
+	}
+	options := newOptions(opts)
+
+	plan := getPlan(tdst.Elem(), tsrc.Elem(), options)
+	for _, fp := range plan.Fields {
+		if fp.Err == nil {
+			continue
+		}
+		if !options.Strict && isMissingCounterpart(fp.Err) {
+			continue
+		}
+		errs = append(errs, fp.Err)
+	}
+	if len(plan.Missing) > 0 {
+		errs = append(errs, &RequiredError{Type: plan.DstType, Missing: plan.Missing})
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &CheckError{
+		Dst:  tdst.Elem(),
+		Src:  tsrc.Elem(),
+		Errs: errs,
+	}
+}
+
+// MustCheck is the shorthand of `Check` in `init()`, it panics if any problem
+// is found.
+func MustCheck(dst, src interface{}, opts ...GlueOption) bool {
+	err := Check(dst, src, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return true
+}
+
+// isMissingCounterpart reports the field simply has no counterpart, which is
+// tolerated in relaxed mode.
+func isMissingCounterpart(err error) bool {
+	return errors.Is(err, ErrUnsatisfiedField) &&
+		!errors.Is(err, ErrAmbiguousField) &&
+		!errors.Is(err, ErrMissingConverter)
+}
+
+func isPtrToStructType(t reflect.Type) bool {
+	return t != nil && t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct
+}
@@ -0,0 +1,119 @@
+package glue
+
+import (
+	"context"
+	"reflect"
+)
+
+// The concrete types allocated for interface types, guarded by `convLock`.
+var concreteMap = make(map[reflect.Type]reflect.Type, 8)
+
+// RegConcrete registers `concrete` as the type allocated for destination
+// fields of the interface type `iface` points to, like
+// `RegConcrete((*Shape)(nil), &Square{})`. A struct (or pointer to struct)
+// source field is then glued into a new value of `concrete` which is assigned
+// to the field, unless a converter is registered for the pair or the source
+// is of the concrete type already. `concrete` is a struct or a pointer to
+// struct implementing the interface, it can be registered for `interface{}`
+// as well.
+func RegConcrete(iface, concrete interface{}) error {
+	typeIface := reflect.TypeOf(iface)
+	if typeIface == nil || typeIface.Kind() != reflect.Ptr ||
+		typeIface.Elem().Kind() != reflect.Interface {
+		return ErrNotInterface
+	}
+	typeIface = typeIface.Elem()
+	typeConcrete := reflect.TypeOf(concrete)
+	if structType(concrete) == nil {
+		return ErrNotStruct
+	}
+	if !typeConcrete.Implements(typeIface) {
+		return ErrNotImplemented
+	}
+
+	convLock.Lock()
+	defer convLock.Unlock()
+	concreteMap[typeIface] = typeConcrete
+	resetPlans()
+	return nil
+}
+
+// MustRegConcrete is the shorthand of `RegConcrete` on initialize, it panics
+// if the types are not valid.
+func MustRegConcrete(iface, concrete interface{}) bool {
+	err := RegConcrete(iface, concrete)
+	if err != nil {
+		panic(err)
+	}
+	return true
+}
+
+// DeregConcrete deregisters the concrete type of the interface type `iface`
+// points to.
+func DeregConcrete(iface interface{}) {
+	typeIface := reflect.TypeOf(iface)
+	if typeIface == nil || typeIface.Kind() != reflect.Ptr {
+		return
+	}
+	convLock.Lock()
+	defer convLock.Unlock()
+	delete(concreteMap, typeIface.Elem())
+	resetPlans()
+}
+
+// lookupConcrete returns the concrete type registered for the interface
+// `dstType`, nil if there is none or `srcType` is not a struct of another
+// type.
+func lookupConcrete(dstType, srcType reflect.Type) reflect.Type {
+	if dstType.Kind() != reflect.Interface || derefType(srcType).Kind() != reflect.Struct {
+		return nil
+	}
+	convLock.RLock()
+	concrete := concreteMap[dstType]
+	convLock.RUnlock()
+	if concrete == nil || derefType(concrete) == derefType(srcType) {
+		return nil
+	}
+	return concrete
+}
+
+// glueConcrete glues the struct `src` into a new value of `concrete` with the
+// hooks called around, a nil pointer gives the zero value of `dstType`. The nested structs are glued
+// with the options of the field, except those about the outer structs.
+func glueConcrete(
+	ctx context.Context, concrete, dstType reflect.Type, src reflect.Value,
+	options *glueOptions,
+) (reflect.Value, error) {
+	if src.Kind() == reflect.Ptr {
+		if src.IsNil() {
+			return reflect.Zero(dstType), nil
+		}
+		src = src.Elem()
+	}
+	nested := *options
+	nested.Only, nested.Except, nested.FieldMask = nil, nil, nil
+	nested.Reverse, nested.UnsafeCopy = false, false
+
+	ptr := reflect.New(derefType(concrete))
+	plan := getPlan(ptr.Elem().Type(), src.Type(), &nested)
+	if err := plan.firstErr(nested.Strict); err != nil {
+		return ptr, err
+	}
+	// the hooks take the pointers, src of an interface is not addressable.
+	srcPtr := reflect.New(src.Type())
+	if src.CanAddr() {
+		srcPtr = src.Addr()
+	} else {
+		srcPtr.Elem().Set(src)
+	}
+	err := plan.glue(
+		ctx, ptr.Interface(), srcPtr.Interface(), ptr.Elem(), srcPtr.Elem(), &nested,
+	)
+	if err != nil {
+		return ptr, err
+	}
+	if concrete.Kind() == reflect.Ptr {
+		return ptr, nil
+	}
+	return ptr.Elem(), nil
+}
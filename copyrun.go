@@ -0,0 +1,165 @@
+package glue
+
+import (
+	"reflect"
+	"strconv"
+	"unsafe"
+)
+
+// copyRun is a run of fields of identical types laid out contiguously in both
+// structs, it is copied as a single memory move with option `DoUnsafeCopy`.
+type copyRun struct {
+	Start, End     int // The range of the fields in the plan.
+	DstOff, SrcOff uintptr
+	// The type spanning the memory of the run, a byte array if the fields
+	// hold no pointer, otherwise a struct laid out as the fields so that the
+	// pointers are copied with write barriers.
+	Type reflect.Type
+}
+
+// copy copies the run from `srcStruct` to `dstStruct`, both are addressable.
+func (r *copyRun) copy(dstStruct, srcStruct reflect.Value) {
+	dst := unsafe.Add(unsafe.Pointer(dstStruct.UnsafeAddr()), r.DstOff)
+	src := unsafe.Add(unsafe.Pointer(srcStruct.UnsafeAddr()), r.SrcOff)
+	reflect.NewAt(r.Type, dst).Elem().Set(reflect.NewAt(r.Type, src).Elem())
+}
+
+// copyRuns returns the runs of the plan if option `DoUnsafeCopy` is given and
+// neither field hook, overwrite policy nor merge mode is, the runs are found
+// on the first call.
+func (p *gluePlan) copyRuns(options *glueOptions) []copyRun {
+	if !options.UnsafeCopy || len(options.FieldHooks) > 0 ||
+		options.Overwrite != OverwriteAlways ||
+		options.SliceMerge != 0 || options.MapMerge != 0 {
+		return nil
+	}
+	p.runsOnce.Do(p.findRuns)
+	return p.runs
+}
+
+// runField is a field of a run candidate.
+type runField struct {
+	DstOff, SrcOff uintptr
+	Type           reflect.Type
+}
+
+// findRuns finds the runs of at least two consecutive fields in the plan
+// which are copied as is and are contiguous in memory on both sides, the
+// fields behind an embedded pointer are never in a run.
+func (p *gluePlan) findRuns() {
+	var (
+		start  int
+		fields []runField
+	)
+	flush := func() {
+		// trims the tail until the run is laid out as the fields.
+		for n := len(fields); n >= 2; n-- {
+			if t := runType(fields[:n]); t != nil {
+				p.runs = append(p.runs, copyRun{
+					Start:  start,
+					End:    start + n,
+					DstOff: fields[0].DstOff,
+					SrcOff: fields[0].SrcOff,
+					Type:   t,
+				})
+				break
+			}
+		}
+		fields = fields[:0]
+	}
+	for i, fp := range p.Fields {
+		if fp.Err != nil || fp.Conv.IsValid() || fp.Dynamic ||
+			fp.Transform.IsValid() || fp.Merge != 0 {
+			flush()
+			continue
+		}
+		dstOff, dstType, dstOk := offsetOf(p.DstType, fp.Dst)
+		srcOff, srcType, srcOk := offsetOf(p.SrcType, fp.Src)
+		if !dstOk || !srcOk || dstType != srcType {
+			flush()
+			continue
+		}
+		if n := len(fields); n > 0 {
+			last := fields[n-1]
+			size := last.Type.Size()
+			if last.DstOff+size != dstOff || last.SrcOff+size != srcOff {
+				flush()
+			}
+		}
+		if len(fields) == 0 {
+			start = i
+		}
+		fields = append(fields, runField{dstOff, srcOff, dstType})
+	}
+	flush()
+}
+
+// runType returns the type spanning the memory of contiguous `fields`, nil if
+// there is no type laid out exactly as them.
+func runType(fields []runField) reflect.Type {
+	var (
+		base    = fields[0].DstOff
+		last    = fields[len(fields)-1]
+		size    = last.DstOff + last.Type.Size() - base
+		pointer = false
+	)
+	for _, f := range fields {
+		pointer = pointer || hasPointers(f.Type)
+	}
+	if !pointer {
+		return reflect.ArrayOf(int(size), reflect.TypeOf(byte(0)))
+	}
+
+	sfs := make([]reflect.StructField, len(fields))
+	for i, f := range fields {
+		sfs[i] = reflect.StructField{Name: "F" + strconv.Itoa(i), Type: f.Type}
+	}
+	t := reflect.StructOf(sfs)
+	// the alignment of a field may move it or pad the tail.
+	if t.Size() != size {
+		return nil
+	}
+	for i, f := range fields {
+		if t.Field(i).Offset != f.DstOff-base {
+			return nil
+		}
+	}
+	return t
+}
+
+// offsetOf returns the offset and the type of the field at `index` in `t`, it
+// reports false if the field is behind an embedded pointer.
+func offsetOf(t reflect.Type, index []int) (uintptr, reflect.Type, bool) {
+	var off uintptr
+	for i, x := range index {
+		if i > 0 && t.Kind() == reflect.Ptr {
+			return 0, nil, false
+		}
+		sf := t.Field(x)
+		off += sf.Offset
+		t = sf.Type
+	}
+	return off, t, true
+}
+
+// hasPointers reports whether a value of `t` holds pointers the GC tracks.
+func hasPointers(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Array:
+		return t.Len() > 0 && hasPointers(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if hasPointers(t.Field(i).Type) {
+				return true
+			}
+		}
+		return false
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16,
+		reflect.Uint32, reflect.Uint64, reflect.Uintptr, reflect.Float32,
+		reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return false
+	default:
+		return true
+	}
+}
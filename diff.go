@@ -0,0 +1,146 @@
+package glue
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Change is a field of dst whose value `Glue` would change, see `Diff`.
+type Change struct {
+	Path string      // The path of the field in dst.
+	Old  interface{} // The value in dst.
+	New  interface{} // The value `Glue` would write, converted and transformed.
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %v -> %v", c.Path, c.Old, c.New)
+}
+
+// Diff reports the fields of dst that `Glue` would change if src were glued
+// into it with the same options, in the order `Glue` writes them, without
+// modifying dst. The fields are matched with the same tags, promotion,
+// mappings and converters as `Glue`, values are compared by
+// `reflect.DeepEqual`. Field hooks are called as `Glue` does, but the hooks
+// of `BeforeGluer` and the like are not.
+// A field of dst behind a nil embedded pointer is reported with the zero
+// value as the old value.
+func Diff(dst, src interface{}, opts ...GlueOption) ([]Change, error) {
+	return DiffContext(context.Background(), dst, src, opts...)
+}
+
+// DiffContext is `Diff` with a context passed to the converters.
+func DiffContext(
+	ctx context.Context, dst, src interface{}, opts ...GlueOption,
+) ([]Change, error) {
+	var (
+		vdst = reflect.ValueOf(dst)
+		vsrc = reflect.ValueOf(src)
+	)
+	if !isValidPtrToStruct(&vdst) || !isValidPtrToStruct(&vsrc) {
+		return nil, ErrNotPtrToStruct
+	}
+
+	options := newOptions(opts)
+
+	dstStruct := vdst.Elem()
+	srcStruct := vsrc.Elem()
+
+	plan := getPlan(dstStruct.Type(), srcStruct.Type(), options)
+	if err := plan.firstErr(options.Strict); err != nil {
+		return nil, err
+	}
+	return plan.diff(ctx, dstStruct, srcStruct, options, false)
+}
+
+// Equal reports whether `a` mirrors `b`, that is every field of `a` matched
+// in `b` holds the value `Glue` would write from `b`, it stops at the first
+// mismatch and returns the path of the field in `a`, empty if equal. The
+// fields are matched and converted the same as `Diff(a, b)`, which lists all
+// the mismatches.
+func Equal(a, b interface{}, opts ...GlueOption) (bool, string, error) {
+	var (
+		va = reflect.ValueOf(a)
+		vb = reflect.ValueOf(b)
+	)
+	if !isValidPtrToStruct(&va) || !isValidPtrToStruct(&vb) {
+		return false, "", ErrNotPtrToStruct
+	}
+
+	options := newOptions(opts)
+
+	aStruct := va.Elem()
+	bStruct := vb.Elem()
+
+	plan := getPlan(aStruct.Type(), bStruct.Type(), options)
+	if err := plan.firstErr(options.Strict); err != nil {
+		return false, "", err
+	}
+	changes, err := plan.diff(context.Background(), aStruct, bStruct, options, true)
+	if err != nil {
+		return false, "", err
+	}
+	if len(changes) > 0 {
+		return false, changes[0].Path, nil
+	}
+	return true, "", nil
+}
+
+// diff lists the changes of fields from `srcStruct` to `dstStruct`, it
+// follows `run` without writing, it stops at the first change if `first`.
+func (p *gluePlan) diff(
+	ctx context.Context, dstStruct, srcStruct reflect.Value, options *glueOptions,
+	first bool,
+) ([]Change, error) {
+	var changes []Change
+	for _, fp := range p.Fields {
+		if fp.Err != nil {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		srcField, srcOk := fieldByIndex(srcStruct, fp.Src, false)
+		if !srcOk && !options.NilAsZero {
+			if options.Strict {
+				return nil, fmt.Errorf(
+					"%w: %#v is behind nil pointer", ErrUnsatisfiedField, fp.Name,
+				)
+			}
+			continue
+		}
+		// `dstField` is zero if `Glue` would allocate the pointers on the way.
+		dstField, _, ok := lookupDst(dstStruct, fp.Dst)
+		if !ok {
+			if options.Strict {
+				return nil, fmt.Errorf(
+					"%w: %#v is not settable", ErrUnsatisfiedField, fp.Name,
+				)
+			}
+			continue
+		}
+		v, write, err := fp.value(ctx, dstField, srcField, srcOk, options)
+		if err != nil {
+			return nil, err
+		}
+		if !write {
+			continue
+		}
+		// as assigned to dst, `v` may be of a type implementing the interface
+		// of dst.
+		nv := reflect.New(dstField.Type()).Elem()
+		nv.Set(v)
+		if reflect.DeepEqual(dstField.Interface(), nv.Interface()) {
+			continue
+		}
+		changes = append(changes, Change{
+			Path: fp.DstPath,
+			Old:  dstField.Interface(),
+			New:  nv.Interface(),
+		})
+		if first {
+			break
+		}
+	}
+	return changes, nil
+}
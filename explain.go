@@ -0,0 +1,243 @@
+package glue
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+)
+
+// Report explains how `Glue` copies fields between two struct types, see
+// `Explain`.
+type Report struct {
+	Dst, Src    reflect.Type
+	FavorSource bool
+	Fields      []FieldReport
+}
+
+// FieldReport explains how one field is copied or why it is skipped.
+type FieldReport struct {
+	Field string // The path of the field seeking its counterpart.
+	Dst   string // The path of the field in dst, empty if not found.
+	Src   string // The path of the field in src, empty if not found.
+	// The name of the converter, empty if not converted, "by dynamic type" if
+	// src is an interface converted by the type of the value it holds, "into"
+	// the concrete type registered for an interface of dst.
+	Converter string
+	Copied    bool
+	Partial   bool   // The fields of the struct are glued one by one, see `Explain`.
+	Reason    string // Why the field is skipped, empty if copied.
+	Err       error  // The error `Glue` returns for the field in strict mode.
+}
+
+// Explain reports how `Glue` would copy fields from src to dst with the same
+// options without copying any data, it lists every field of dst (or src under
+// `DoFavorSource`), the field it pairs with, the converter used and why the
+// field is skipped if it is. The parameters are the same as `Check`.
+// A field masked out by `DoOnly`, `DoExcept` or `DoFieldMask` is reported as
+// such, a struct field selected partially, or mapped by the paths of its
+// fields, is reported as `Partial` followed by the nested fields copied.
+func Explain(dst, src interface{}, opts ...GlueOption) (*Report, error) {
+	var (
+		tdst = reflect.TypeOf(dst)
+		tsrc = reflect.TypeOf(src)
+	)
+	if !isPtrToStructType(tdst) || !isPtrToStructType(tsrc) {
+		return nil, ErrNotPtrToStruct
+	}
+	options := newOptions(opts)
+	dstType, srcType := tdst.Elem(), tsrc.Elem()
+
+	var (
+		plan     = getPlan(dstType, srcType, options)
+		mapping  = getMapping(dstType, srcType)
+		seeker   = dstType
+		favorSrc = options.FavorSource != options.Reverse
+		reported = make(map[*fieldPlan]bool, len(plan.Fields))
+		report   = &Report{
+			Dst:         dstType,
+			Src:         srcType,
+			FavorSource: options.FavorSource,
+		}
+	)
+	if options.Reverse {
+		mapping = getMapping(srcType, dstType)
+	}
+	// the plan without masks tells the fields masked out.
+	var unmasked *gluePlan
+	if options.Only != nil || options.Except != nil || options.FieldMask != nil {
+		base := *options
+		base.Only, base.Except, base.FieldMask = nil, nil, nil
+		unmasked = getPlan(dstType, srcType, &base)
+	}
+	// under `DoReverse` the fields seek as in the opposite direction.
+	if favorSrc {
+		seeker = srcType
+	}
+	for i := 0; i < seeker.NumField(); i++ {
+		sf := seeker.Field(i)
+		if sf.PkgPath != "" {
+			report.Fields = append(report.Fields, FieldReport{
+				Field:  sf.Name,
+				Reason: "unexported",
+			})
+			continue
+		}
+		found := false
+		for _, fp := range plan.Fields {
+			if fp.Path != sf.Name {
+				continue
+			}
+			found = true
+			reported[fp] = true
+			report.Fields = append(report.Fields, explainField(fp, dstType, srcType))
+		}
+		if found {
+			continue
+		}
+		// the fields glued one by one in the struct, by masks or mapping.
+		var nested []FieldReport
+		for _, np := range plan.Fields {
+			if strings.HasPrefix(np.Path, sf.Name+".") {
+				reported[np] = true
+				nested = append(nested, explainField(np, dstType, srcType))
+			}
+		}
+		if fp := unmasked.fieldOf(sf.Name); fp != nil || len(nested) > 0 {
+			fr := FieldReport{Field: sf.Name, Partial: true}
+			if fp != nil {
+				fr = explainField(fp, dstType, srcType)
+				fr.Copied, fr.Err, fr.Reason = false, nil, "masked out"
+			}
+			if len(nested) > 0 {
+				fr.Partial, fr.Reason = true, ""
+			}
+			report.Fields = append(append(report.Fields, fr), nested...)
+			continue
+		}
+		reason := "ignored by tag"
+		switch {
+		case mapping != nil:
+			reason = "ignored by mapping"
+		case !visibleToGroups(sf, options):
+			reason = "not in active groups"
+		}
+		report.Fields = append(report.Fields, FieldReport{
+			Field:  sf.Name,
+			Reason: reason,
+		})
+	}
+	for _, fp := range plan.Fields {
+		if !reported[fp] {
+			report.Fields = append(report.Fields, explainField(fp, dstType, srcType))
+		}
+	}
+	return report, nil
+}
+
+// fieldOf returns the field of the plan seeking by `path`, nil if there is
+// none or the plan is nil.
+func (p *gluePlan) fieldOf(path string) *fieldPlan {
+	if p == nil {
+		return nil
+	}
+	for _, fp := range p.Fields {
+		if fp.Path == path {
+			return fp
+		}
+	}
+	return nil
+}
+
+// visibleToGroups reports whether the field is not hidden by its groups, see
+// `DoGroups`.
+func visibleToGroups(sf reflect.StructField, options *glueOptions) bool {
+	raw, exist := sf.Tag.Lookup(glueTagKey)
+	if !exist {
+		return true
+	}
+	gt, err := parseGlueAttrs(raw)
+	return err != nil || gt.Groups == nil || options.inGroups(gt.Groups)
+}
+
+func explainField(fp *fieldPlan, dstType, srcType reflect.Type) FieldReport {
+	fr := FieldReport{
+		Field:  fp.Path,
+		Copied: fp.Err == nil,
+		Err:    fp.Err,
+	}
+	if fp.Dst != nil {
+		fr.Dst = pathOf(dstType, fp.Dst)
+	}
+	if fp.Src != nil {
+		fr.Src = pathOf(srcType, fp.Src)
+	}
+	if fp.Conv.IsValid() {
+		fr.Converter = funcName(fp.Conv)
+	}
+	if fp.Dynamic {
+		fr.Converter = "by dynamic type"
+	}
+	if fp.Concrete != nil {
+		fr.Converter = "into " + fp.Concrete.String()
+	}
+	if fp.Err != nil {
+		// strip the prefix of the base error.
+		fr.Reason = strings.TrimPrefix(fp.Err.Error(), ErrGlue.Error()+": ")
+	}
+	return fr
+}
+
+// String formats the report as a table.
+func (r *Report) String() string {
+	var (
+		buf   bytes.Buffer
+		w     = tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+		arrow = "<-"
+	)
+	if r.FavorSource {
+		arrow = "->"
+	}
+	fmt.Fprintf(&buf, "glue %v -> %v\n", r.Src, r.Dst)
+	for _, fr := range r.Fields {
+		switch {
+		case fr.Partial:
+			fmt.Fprintf(w, "  %s\tpartially copied\n", fr.Field)
+		case !fr.Copied:
+			fmt.Fprintf(w, "  %s\tskipped: %s\n", fr.Field, fr.Reason)
+		case r.FavorSource && fr.Converter != "":
+			fmt.Fprintf(w, "  %s\t%s %s\t(%s)\n", fr.Src, arrow, fr.Dst, fr.Converter)
+		case r.FavorSource:
+			fmt.Fprintf(w, "  %s\t%s %s\n", fr.Src, arrow, fr.Dst)
+		case fr.Converter != "":
+			fmt.Fprintf(w, "  %s\t%s %s\t(%s)\n", fr.Dst, arrow, fr.Src, fr.Converter)
+		default:
+			fmt.Fprintf(w, "  %s\t%s %s\n", fr.Dst, arrow, fr.Src)
+		}
+	}
+	w.Flush()
+	return buf.String()
+}
+
+// pathOf turns an index sequence into the field names joined by dot.
+func pathOf(t reflect.Type, index []int) string {
+	names := make([]string, len(index))
+	for i, x := range index {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		sf := t.Field(x)
+		names[i] = sf.Name
+		t = sf.Type
+	}
+	return strings.Join(names, ".")
+}
+
+func funcName(fn reflect.Value) string {
+	if f := runtime.FuncForPC(fn.Pointer()); f != nil {
+		return f.Name()
+	}
+	return fn.Type().String()
+}
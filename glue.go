@@ -1,22 +1,29 @@
 package glue
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
 	"unicode"
 	"unicode/utf8"
 )
 
-// NOTE: `FieldByName` is slow but cacheable, yet the side effect of using mutex
-// lock to protect the cache cancels out the benefit of caching it.
+// NOTE: `FieldByName` is slow, the fields are only looked up when a plan is
+// built, `getPlan` caches the plans of type pairs, see plan.go.
 
 const (
 	// struct tag
 	glueTagKey = "glue"
 	// tag attr
-	attrIgnr = "-"
+	attrIgnr      = "-"
+	attrTransform = "transform"
+	attrMerge     = "merge"
+	attrGroups    = "groups"
+	attrRequired  = "required"
+	attrNonZero   = "nonzero"
 )
 
 var (
@@ -25,15 +32,42 @@ var (
 	ErrNotFunction       = fmt.Errorf("%w: the `converter` fed in is not a function", ErrGlue)
 	ErrIncompatSignature = fmt.Errorf("%w: function signature incompatible", ErrGlue)
 	ErrUnsatisfiedField  = fmt.Errorf("%w: unsatisfied field", ErrGlue)
+	ErrNotStruct         = fmt.Errorf("%w: one of the type hints is not struct", ErrGlue)
+	ErrUnknownField      = fmt.Errorf("%w: no such exported field", ErrGlue)
+	ErrInvalidTag        = fmt.Errorf("%w: invalid tag", ErrGlue)
+	ErrAmbiguousField    = fmt.Errorf("%w: ambiguous field", ErrUnsatisfiedField)
+	ErrMissingConverter  = fmt.Errorf("%w: no converter registered", ErrUnsatisfiedField)
+	ErrHook              = fmt.Errorf("%w: hook failed", ErrGlue)
+	ErrUnknownTransform  = fmt.Errorf("%w: unknown transform", ErrGlue)
+	ErrNotSliceOfStruct  = fmt.Errorf("%w: one of the arguments is not slice of struct", ErrGlue)
+	ErrNotChanOfStruct   = fmt.Errorf("%w: one of the arguments is not channel or iterator of struct", ErrGlue)
+	ErrRequiredField     = fmt.Errorf("%w: required field", ErrGlue)
+	ErrNotInterface      = fmt.Errorf("%w: the type hint is not pointer to interface", ErrGlue)
+	ErrNotImplemented    = fmt.Errorf("%w: the concrete type does not implement the interface", ErrGlue)
 )
 
 type fieldAttr struct {
 	Alias     string // The name a field used to pull/push from/to another struct.
 	FieldMeta reflect.StructField
+	Err       error  // The tag of the field is invalid.
+	Transform string // The name of the transform from the `glue` tag.
+	Merge     MergeMode
+	Groups    []string // The groups the field is visible to, nil means all.
+	Required  bool     // The field must have a counterpart.
+	NonZero   bool     // The field must be non-zero after gluing.
 }
 type typeAttr struct {
 	ExportedNum int // the number of available/settable fields.
 	FieldAttrs  []*fieldAttr
+	Aliases     map[string]*fieldAttr // fields indexed by alias.
+}
+
+// The key of attribute cache, the same type read with different tag keys or
+// groups has different attributes.
+type attrKey struct {
+	Type   reflect.Type
+	Tags   string
+	Groups string // `glueOptions.groupSig`
 }
 
 // The key of map of conversion functions.
@@ -42,9 +76,17 @@ type typeMapKey struct {
 	Src reflect.Type
 }
 
+var (
+	typeContext = reflect.TypeOf((*context.Context)(nil)).Elem()
+	typeError   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// The tag keys read by default.
+var defaultTagKeys = []string{glueTagKey}
+
 var (
 	cacheLock sync.Mutex
-	attrCache = make(map[reflect.Type]*typeAttr, 32)
+	attrCache = make(map[attrKey]*typeAttr, 32)
 	convLock  sync.RWMutex
 	typeMap   = make(map[typeMapKey]reflect.Value, 32)
 )
@@ -55,97 +97,34 @@ var (
 // equally numbers of fields).
 // `Glue` assumes that dst struct serves as a temporary storage of data and does
 // not perform deepcopy on each field that is being copied from.
+// If dst implements `BeforeGluer` or `AfterGluer`, the hooks are called around
+// the copy, and so are `BeforeGlueOuter` and `AfterGlueOuter` of src under
+// `DoFavorSource`, `Glue` aborts with `*HookError` if a hook fails.
 func Glue(dst, src interface{}, opts ...GlueOption) error {
+	return GlueContext(context.Background(), dst, src, opts...)
+}
+
+// GlueContext is `Glue` with a context, it checks `ctx.Err()` between fields
+// and passes `ctx` to the converters that take a context.
+func GlueContext(ctx context.Context, dst, src interface{}, opts ...GlueOption) error {
 	var (
-		options glueOptions
-		exist   bool
-		vdst    = reflect.ValueOf(dst)
-		vsrc    = reflect.ValueOf(src)
-		// reflect stuffs
-		dstStruct, srcStruct       reflect.Value
-		dstType, srcType           reflect.Type
-		alias                      string
-		dstFieldMeta, srcFieldMeta reflect.StructField
-		dstField, srcField         reflect.Value
-		fAttrs                     *typeAttr
+		vdst = reflect.ValueOf(dst)
+		vsrc = reflect.ValueOf(src)
 	)
 	if !isValidPtrToStruct(&vdst) || !isValidPtrToStruct(&vsrc) {
 		return ErrNotPtrToStruct
 	}
 
-	for _, opt := range opts {
-		opt.apply(&options)
-	}
+	options := newOptions(opts)
 
-	dstStruct = vdst.Elem()
-	srcStruct = vsrc.Elem()
-	dstType = dstStruct.Type()
-	srcType = srcStruct.Type()
+	dstStruct := vdst.Elem()
+	srcStruct := vsrc.Elem()
 
-	if options.FavorSource {
-		fAttrs = getTypeAttr(srcType)
-	} else {
-		fAttrs = getTypeAttr(dstType)
+	plan := getPlan(dstStruct.Type(), srcStruct.Type(), options)
+	if err := plan.firstErr(options.Strict); err != nil {
+		return err
 	}
-
-	for _, fa := range fAttrs.FieldAttrs {
-		alias = fa.Alias
-
-		if options.FavorSource {
-			srcFieldMeta = fa.FieldMeta
-			dstFieldMeta, exist = dstType.FieldByName(alias)
-		} else {
-			dstFieldMeta = fa.FieldMeta
-			srcFieldMeta, exist = srcType.FieldByName(alias)
-		}
-
-		if !exist {
-			if options.Strict {
-				return fmt.Errorf("%w: %#v", ErrUnsatisfiedField, alias)
-			}
-			continue
-		}
-
-		// test if types are strictly equal.
-		var (
-			fconv  reflect.Value
-			doConv bool
-		)
-		if dstFieldMeta.Type != srcFieldMeta.Type {
-			mk := typeMapKey{
-				Dst: dstFieldMeta.Type,
-				Src: srcFieldMeta.Type,
-			}
-			convLock.RLock()
-			fconv, exist = typeMap[mk]
-			convLock.RUnlock()
-			doConv = exist
-			if !exist {
-				if options.Strict {
-					return fmt.Errorf("%w: %#v", ErrUnsatisfiedField, alias)
-				}
-				continue
-			}
-		}
-		dstField = dstStruct.FieldByIndex(dstFieldMeta.Index)
-		srcField = srcStruct.FieldByIndex(srcFieldMeta.Index)
-
-		if !dstField.CanSet() || !srcField.CanSet() {
-			continue
-		}
-		var v reflect.Value
-		if !doConv {
-			v = reflect.ValueOf(srcField.Interface())
-		} else {
-			ret := fconv.Call(
-				[]reflect.Value{reflect.ValueOf(srcField.Interface())},
-			)
-			v = reflect.ValueOf(ret[0].Interface())
-		}
-		dstField.Set(v)
-
-	}
-	return nil
+	return plan.glue(ctx, dst, src, dstStruct, srcStruct, options)
 }
 
 func isValidPtrToStruct(rv *reflect.Value) bool {
@@ -163,6 +142,16 @@ func isValidPtrToStruct(rv *reflect.Value) bool {
 	return true
 }
 
+// isValidPath checks if a string is valid golang identifiers joined by dot.
+func isValidPath(s string) bool {
+	for _, name := range strings.Split(s, ".") {
+		if !isValidIdentifier(name) {
+			return false
+		}
+	}
+	return true
+}
+
 // isValidIdentifier checks if a string is a valid golang identifier.
 func isValidIdentifier(s string) bool {
 	var (
@@ -203,10 +192,32 @@ iter_rune:
 // correct function signature, if the converter is not a function or does not
 // have the right signature, `RegConv` returns corresponding error,
 // on successful register, this function returns nil.
+// The converter may also take a context and return an error, in the form of
+// `func(context.Context, S) (D, error)`, the context is the one passed to
+// `GlueContext`, an error aborts `Glue` as a `*FieldError`.
 func RegConv(tDst, tSrc, converter interface{}) error {
 	typeDst := reflect.ValueOf(tDst).Type()
 	typeSrc := reflect.ValueOf(tSrc).Type()
 	vConvFunc := reflect.ValueOf(converter)
+	if err := checkConverter(vConvFunc, typeDst, typeSrc); err != nil {
+		return err
+	}
+
+	convLock.Lock()
+	defer convLock.Unlock()
+	mk := typeMapKey{
+		Dst: typeDst,
+		Src: typeSrc,
+	}
+	typeMap[mk] = vConvFunc
+	resetPlans()
+
+	return nil
+}
+
+// checkConverter checks `vConvFunc` is a function that takes a value of
+// `typeSrc` and outputs `typeDst`.
+func checkConverter(vConvFunc reflect.Value, typeDst, typeSrc reflect.Type) error {
 	if vConvFunc.Kind() != reflect.Func {
 		return ErrNotFunction
 	}
@@ -215,19 +226,28 @@ func RegConv(tDst, tSrc, converter interface{}) error {
 		[]reflect.Type{typeDst},
 		false,
 	)
-	if vConvFunc.Type() != vfunc {
+	vfuncCtx := reflect.FuncOf(
+		[]reflect.Type{typeContext, typeSrc},
+		[]reflect.Type{typeDst, typeError},
+		false,
+	)
+	if t := vConvFunc.Type(); t != vfunc && t != vfuncCtx {
 		return ErrIncompatSignature
 	}
+	return nil
+}
 
-	convLock.Lock()
-	defer convLock.Unlock()
-	mk := typeMapKey{
-		Dst: typeDst,
-		Src: typeSrc,
+// callConv calls the converter `fconv` on `v`, passing `ctx` if the converter
+// takes a context.
+func callConv(ctx context.Context, fconv, v reflect.Value) (reflect.Value, error) {
+	if fconv.Type().NumIn() == 1 {
+		return fconv.Call([]reflect.Value{v})[0], nil
 	}
-	typeMap[mk] = vConvFunc
-
-	return nil
+	ret := fconv.Call([]reflect.Value{reflect.ValueOf(ctx), v})
+	if err, _ := ret[1].Interface().(error); err != nil {
+		return ret[0], err
+	}
+	return ret[0], nil
 }
 
 // DeregConv deregisters the conversion mapping between two types.
@@ -241,6 +261,7 @@ func DeregConv(tDst, tSrc interface{}) {
 		Src: typeSrc,
 	}
 	delete(typeMap, mk)
+	resetPlans()
 }
 
 // MustRegConv is a shorthand allow user register conversion map on initialize,
@@ -253,66 +274,172 @@ func MustRegConv(tDst, tSrc, converter interface{}) bool {
 	return true
 }
 
+// lookupCounterpart finds the field `alias` refers to in type `t`, the alias
+// can be a path like `Emb.ID` to pick one of the ambiguous promoted fields.
+// If tag keys are chosen explicitly, it falls back to the field aliased as
+// `alias` in `t`.
+func lookupCounterpart(
+	t reflect.Type, alias string, options *glueOptions,
+) (reflect.StructField, error) {
+	if strings.IndexByte(alias, '.') >= 0 {
+		index, ft, ok := resolvePath(t, alias)
+		if ok {
+			return reflect.StructField{Name: alias, Index: index, Type: ft}, nil
+		}
+	}
+	sf, err := lookupField(t, alias)
+	if err == nil || options.TagKeys == nil {
+		return sf, err
+	}
+	fa, exist := getTypeAttr(t, options).Aliases[alias]
+	if exist {
+		return fa.FieldMeta, nil
+	}
+	return sf, err
+}
+
+// lookupField finds field `name` in `t` as `reflect.Type.FieldByName` does, but
+// tells an ambiguous name from a missing one.
+func lookupField(t reflect.Type, name string) (reflect.StructField, error) {
+	sf, exist := t.FieldByName(name)
+	if exist {
+		return sf, nil
+	}
+	if paths := promotedPaths(t, name); len(paths) > 1 {
+		return sf, fmt.Errorf(
+			"%w: %#v is promoted from %s", ErrAmbiguousField, name,
+			strings.Join(paths, ", "),
+		)
+	}
+	return sf, fmt.Errorf("%w: %#v", ErrUnsatisfiedField, name)
+}
+
 // getTypeAttr returns cache of `*typeAttr`, it builds attribute if no cache
 // can be acquired.
-func getTypeAttr(t reflect.Type) *typeAttr {
+func getTypeAttr(t reflect.Type, options *glueOptions) *typeAttr {
 	var (
 		dstAttrs *typeAttr
-		rawAttrs string
+		alias    string
+		ignore   bool
+		err      error
 		fAttr    *fieldAttr
 		exist    bool
+		tagKeys  = options.TagKeys
 	)
+	if tagKeys == nil {
+		tagKeys = defaultTagKeys
+	}
 	dstNumFields := t.NumField()
+	key := attrKey{Type: t, Tags: options.tagSig, Groups: options.groupSig}
 
 	cacheLock.Lock()
 	defer cacheLock.Unlock()
-	dstAttrs, exist = attrCache[t]
+	dstAttrs, exist = attrCache[key]
 	if exist {
 		return dstAttrs
 	}
-	dstAttrs = new(typeAttr)
-	for i := 0; i < dstNumFields; i++ {
-		fieldMeta := t.Field(i)
+	dstAttrs = &typeAttr{
+		Aliases: make(map[string]*fieldAttr, dstNumFields),
+	}
+	for _, fieldMeta := range availableFields(t, nil) {
 		// `attrMap` and `fieldArr` only records "available" fields:
-		// 1) natively exported.
+		// 1) natively exported or promoted from unexported embedded struct.
 		// 2) not tagged as ignored.
+		// 3) visible to the groups of `DoGroups` if tagged with groups.
 
-		// backport of method `IsExported(1.17-)`
-		if !(fieldMeta.PkgPath == "") {
-			continue
-		}
-
-		rawAttrs, exist = fieldMeta.Tag.Lookup(glueTagKey)
-		if !exist {
-			// early out, has no glue tag, pullname is field name.
-			fAttr = &fieldAttr{
-				Alias:     fieldMeta.Name,
-				FieldMeta: fieldMeta,
-			}
-			dstAttrs.ExportedNum++
-			dstAttrs.FieldAttrs = append(dstAttrs.FieldAttrs, fAttr)
-			continue
+		alias, ignore, err = lookupTagAlias(fieldMeta.Tag, tagKeys)
+		if err != nil {
+			// keep the field so the error is reported when it is used.
+			err = fmt.Errorf("%w: field %#v", err, fieldMeta.Name)
+			ignore = false
+			alias = fieldMeta.Name
 		}
-		if rawAttrs == attrIgnr {
+		if ignore {
 			// ignore the field.
 			continue
 		}
-		if !isValidIdentifier(rawAttrs) {
-			panic(fmt.Errorf("%q is not a valid identifier", rawAttrs))
+		if alias == "" {
+			// has no alias, pullname is field name.
+			alias = fieldMeta.Name
 		}
 		fAttr = &fieldAttr{
+			Alias:     alias,
 			FieldMeta: fieldMeta,
+			Err:       err,
+		}
+		if raw, exist := fieldMeta.Tag.Lookup(glueTagKey); exist && err == nil {
+			// attributes are always read from `glue` tag.
+			gt, err := parseGlueAttrs(raw)
+			if err != nil {
+				fAttr.Err = fmt.Errorf("%w: field %#v", err, fieldMeta.Name)
+			}
+			fAttr.Transform = gt.Transform
+			fAttr.Merge = gt.Merge
+			fAttr.Groups = gt.Groups
+			fAttr.Required = gt.Required || gt.NonZero
+			fAttr.NonZero = gt.NonZero
+		}
+		if fAttr.Groups != nil && !options.inGroups(fAttr.Groups) {
+			continue
 		}
-
-		// do parse below this line if allow multiple attributes.
-		// rawAttrs = strings.Split()
-		// and do analyze, if required so.
-		fAttr.Alias = rawAttrs
 
 		dstAttrs.ExportedNum++
 		dstAttrs.FieldAttrs = append(dstAttrs.FieldAttrs, fAttr)
+		if fAttr.Err == nil {
+			dstAttrs.Aliases[alias] = fAttr
+		}
 	}
-	attrCache[t] = dstAttrs
+	attrCache[key] = dstAttrs
 
 	return dstAttrs
 }
+
+// availableFields lists the exported fields of `t`, including the exported
+// fields promoted from unexported embedded structs, which are settable but
+// cannot be reached through the embedded struct itself.
+func availableFields(t reflect.Type, visited map[reflect.Type]bool) []reflect.StructField {
+	if visited == nil {
+		visited = make(map[reflect.Type]bool)
+	}
+	visited[t] = true
+	fields := make([]reflect.StructField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		fieldMeta := t.Field(i)
+		// backport of method `IsExported(1.17-)`
+		if fieldMeta.PkgPath == "" {
+			fields = append(fields, fieldMeta)
+			continue
+		}
+		if !fieldMeta.Anonymous {
+			continue
+		}
+		ft := fieldMeta.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() != reflect.Struct || visited[ft] {
+			continue
+		}
+		for _, inner := range availableFields(ft, visited) {
+			// skip fields shadowed by or conflicting with others.
+			promoted, exist := t.FieldByName(inner.Name)
+			if !exist || !isPromotedFrom(promoted.Index, i, inner.Index) {
+				continue
+			}
+			fields = append(fields, promoted)
+		}
+	}
+	return fields
+}
+
+func isPromotedFrom(index []int, outer int, inner []int) bool {
+	if len(index) != len(inner)+1 || index[0] != outer {
+		return false
+	}
+	for i, x := range inner {
+		if index[i+1] != x {
+			return false
+		}
+	}
+	return true
+}
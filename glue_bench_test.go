@@ -374,3 +374,123 @@ func BenchmarkTimeConversion(b *testing.B) {
 		glue.Glue(u, v)
 	}
 }
+
+func BenchmarkGlueSlice(b *testing.B) {
+	models := makeModels(1024)
+	var dtos []*sDTO
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		glue.GlueSlice(&dtos, models)
+	}
+}
+
+func BenchmarkGlueSliceParallel(b *testing.B) {
+	models := makeModels(1024)
+	opts := []glue.GlueOption{
+		glue.DoParallel(runtime.NumCPU()),
+	}
+	var dtos []*sDTO
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		glue.GlueSlice(&dtos, models, opts...)
+	}
+}
+
+func BenchmarkGluePlain(b *testing.B) {
+	type Plain struct {
+		A int64
+		B string
+		C []byte
+		D float64
+		E bool
+		F uint32
+	}
+	u := &Plain{}
+	v := &Plain{A: 1, B: "b", C: []byte("c"), D: 4.0, E: true, F: 6}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		glue.Glue(u, v)
+	}
+}
+
+func BenchmarkGluePlainDive(b *testing.B) {
+	type Embed struct {
+		A int
+		B string
+	}
+	type Uniform struct {
+		Embed
+		C float64
+	}
+	type Victor struct {
+		A int
+		B string
+		C float64
+	}
+	u := &Uniform{Embed: Embed{A: 1, B: "b"}, C: 3.0}
+	v := &Victor{}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		glue.Glue(v, u)
+	}
+}
+
+func benchmarkWide(b *testing.B, opts ...glue.GlueOption) {
+	type (
+		Model struct {
+			ID       int64
+			Name     string
+			Email    string
+			Age      int32
+			Level    int32
+			Score    float64
+			Tags     []string
+			Parent   *int64
+			Created  int64
+			Updated  int64
+			Note     string
+			Verified bool
+		}
+		DTO struct {
+			ID       int64
+			Name     string
+			Email    string
+			Age      int32
+			Level    int32
+			Score    float64
+			Tags     []string
+			Parent   *int64
+			Created  int64
+			Updated  int64
+			Note     string
+			Verified bool
+		}
+	)
+	parent := int64(1)
+	m := &Model{
+		ID: 2, Name: "name", Email: "email", Age: 3, Level: 4, Score: 5,
+		Tags: []string{"tag"}, Parent: &parent, Created: 6, Updated: 7,
+		Note: "note", Verified: true,
+	}
+	d := &DTO{}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		glue.Glue(d, m, opts...)
+	}
+}
+
+func BenchmarkGlueWide(b *testing.B) {
+	benchmarkWide(b)
+}
+
+func BenchmarkGlueWideUnsafe(b *testing.B) {
+	benchmarkWide(b, glue.DoUnsafeCopy())
+}
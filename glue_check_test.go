@@ -0,0 +1,165 @@
+package glue_test
+
+import (
+	"errors"
+	"glue"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckClean(t *testing.T) {
+	type Foo struct {
+		A int
+		B string `glue:"C"`
+	}
+	type Bar struct {
+		A int
+		C string
+	}
+	assert.NoError(t, glue.Check(&Foo{}, &Bar{}, glue.DoStrict()))
+	assert.NoError(t, glue.Check((*Foo)(nil), (*Bar)(nil)))
+	assert.NotPanics(t, func() {
+		_ = glue.MustCheck(&Foo{}, &Bar{})
+	})
+}
+
+func TestCheckProblems(t *testing.T) {
+	type (
+		EmbA struct {
+			ID int
+		}
+		EmbB struct {
+			ID int
+		}
+		Foo struct {
+			ID      int
+			Missing int
+			Name    string
+			Bad     int `glue:"1st"`
+		}
+		Bar struct {
+			EmbA
+			EmbB
+			Name []byte
+			Bad  int
+		}
+	)
+	var (
+		err      error
+		checkErr *glue.CheckError
+	)
+
+	err = glue.Check(&Foo{}, &Bar{})
+	assert.ErrorIs(t, err, glue.ErrInvalidTag)
+	assert.ErrorIs(t, err, glue.ErrAmbiguousField)
+	assert.ErrorIs(t, err, glue.ErrMissingConverter)
+	assert.ErrorIs(t, err, glue.ErrGlue)
+	assert.True(t, errors.As(err, &checkErr))
+	assert.Len(t, checkErr.Errs, 3)
+	assert.Contains(t, err.Error(), "EmbA.ID, EmbB.ID")
+
+	err = glue.Check(&Foo{}, &Bar{}, glue.DoStrict())
+	assert.True(t, errors.As(err, &checkErr))
+	assert.Len(t, checkErr.Errs, 4)
+
+	assert.Panics(t, func() {
+		_ = glue.MustCheck(&Foo{}, &Bar{})
+	})
+	assert.Equal(t, glue.ErrNotPtrToStruct, glue.Check(Foo{}, &Bar{}))
+}
+
+func TestCheckMapping(t *testing.T) {
+	type Foo struct {
+		A int
+		B int
+	}
+	type Bar struct {
+		C string
+	}
+	glue.NewMapping(Foo{}, Bar{}).Field("A", "C").MustRegister()
+	defer glue.DeregMapping(Foo{}, Bar{})
+
+	err := glue.Check(&Foo{}, &Bar{})
+	assert.ErrorIs(t, err, glue.ErrMissingConverter)
+	assert.NotErrorIs(t, err, glue.ErrAmbiguousField)
+}
+
+func TestGlueAmbiguous(t *testing.T) {
+	type (
+		EmbA struct {
+			ID int
+		}
+		EmbB struct {
+			ID int
+		}
+		Foo struct {
+			ID int
+		}
+		Bar struct {
+			EmbA
+			EmbB
+		}
+	)
+	f := &Foo{ID: -1}
+	b := &Bar{EmbA{1}, EmbB{2}}
+
+	err := glue.Glue(f, b)
+	assert.NoError(t, err)
+	assert.Equal(t, -1, f.ID)
+
+	err = glue.Glue(f, b, glue.DoStrict())
+	assert.ErrorIs(t, err, glue.ErrAmbiguousField)
+	assert.ErrorIs(t, err, glue.ErrUnsatisfiedField)
+}
+
+func TestAmbiguousPick(t *testing.T) {
+	type (
+		EmbA struct {
+			ID int
+		}
+		EmbB struct {
+			ID int
+		}
+		Foo struct {
+			ID int `glue:"EmbB.ID"`
+		}
+		Baz struct {
+			ID int
+		}
+		Bar struct {
+			EmbA
+			*EmbB
+		}
+	)
+	f := &Foo{ID: -1}
+	b := &Bar{EmbA{1}, &EmbB{2}}
+
+	assert.NoError(t, glue.Check(&Foo{}, &Bar{}, glue.DoStrict()))
+	err := glue.Glue(f, b, glue.DoStrict())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, f.ID)
+
+	// pick by tag on source under favor source.
+	type Qux struct {
+		EmbA
+		EmbB
+	}
+	type Quux struct {
+		ID int `glue:"EmbA.ID"`
+	}
+	q := &Qux{}
+	err = glue.Glue(q, &Quux{ID: 4}, glue.DoFavorSource(), glue.DoStrict())
+	assert.NoError(t, err)
+	assert.Equal(t, 4, q.EmbA.ID)
+	assert.Equal(t, 0, q.EmbB.ID)
+
+	// pick by mapping.
+	glue.NewMapping(Baz{}, Bar{}).Field("ID", "EmbA.ID").MustRegister()
+	defer glue.DeregMapping(Baz{}, Bar{})
+	assert.NoError(t, glue.Check(&Baz{}, &Bar{}, glue.DoStrict()))
+	z := &Baz{}
+	err = glue.Glue(z, b, glue.DoStrict())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, z.ID)
+}
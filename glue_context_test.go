@@ -0,0 +1,121 @@
+package glue_test
+
+import (
+	"context"
+	"errors"
+	"glue"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type userID int64
+
+type ctxKeyNames struct{}
+
+var errUnknownUser = errors.New("unknown user")
+
+func resolveUserName(ctx context.Context, id userID) (string, error) {
+	names, _ := ctx.Value(ctxKeyNames{}).(map[userID]string)
+	name, exist := names[id]
+	if !exist {
+		return "", errUnknownUser
+	}
+	return name, nil
+}
+
+func TestGlueContextConverter(t *testing.T) {
+	type Foo struct {
+		Owner string
+	}
+	type Bar struct {
+		Owner userID
+	}
+	err := glue.RegConv("", userID(0), resolveUserName)
+	assert.NoError(t, err)
+	defer glue.DeregConv("", userID(0))
+
+	ctx := context.WithValue(
+		context.Background(), ctxKeyNames{}, map[userID]string{1: "glue"},
+	)
+	f := &Foo{}
+	err = glue.GlueContext(ctx, f, &Bar{Owner: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "glue", f.Owner)
+
+	var fieldErr *glue.FieldError
+	err = glue.GlueContext(ctx, f, &Bar{Owner: 2})
+	assert.ErrorIs(t, err, errUnknownUser)
+	assert.True(t, errors.As(err, &fieldErr))
+	assert.Equal(t, "Owner", fieldErr.Path)
+
+	// `Glue` passes a background context.
+	err = glue.Glue(f, &Bar{Owner: 1})
+	assert.ErrorIs(t, err, errUnknownUser)
+}
+
+func TestGlueContextCancel(t *testing.T) {
+	type Foo struct {
+		A int
+		B string
+		C int
+	}
+	type Bar struct {
+		A int
+		B userID
+		C int
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelOnce := func(ctx context.Context, id userID) (string, error) {
+		cancel()
+		return "cancelled", nil
+	}
+	err := glue.RegConv("", userID(0), cancelOnce)
+	assert.NoError(t, err)
+	defer glue.DeregConv("", userID(0))
+
+	f := &Foo{}
+	err = glue.GlueContext(ctx, f, &Bar{A: 1, B: 2, C: 3})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, f.A)
+	assert.Equal(t, "cancelled", f.B)
+	assert.Equal(t, 0, f.C)
+
+	f = &Foo{}
+	err = glue.GlueContext(ctx, f, &Bar{A: 1})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, f.A)
+}
+
+func TestRegConvContextSignature(t *testing.T) {
+	var err error
+	err = glue.RegConv("", userID(0), func(context.Context, userID) string {
+		return ""
+	})
+	assert.ErrorIs(t, err, glue.ErrIncompatSignature)
+	err = glue.RegConv("", userID(0), func(userID, context.Context) (string, error) {
+		return "", nil
+	})
+	assert.ErrorIs(t, err, glue.ErrIncompatSignature)
+
+	type Foo struct {
+		Owner string
+	}
+	type Bar struct {
+		ID userID
+	}
+	err = glue.NewMapping(Foo{}, Bar{}).
+		Field("Owner", "ID").
+		Convert("Owner", resolveUserName).
+		Register()
+	assert.NoError(t, err)
+	defer glue.DeregMapping(Foo{}, Bar{})
+
+	ctx := context.WithValue(
+		context.Background(), ctxKeyNames{}, map[userID]string{7: "seven"},
+	)
+	f := &Foo{}
+	err = glue.GlueContext(ctx, f, &Bar{ID: 7})
+	assert.NoError(t, err)
+	assert.Equal(t, "seven", f.Owner)
+}
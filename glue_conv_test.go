@@ -1,6 +1,7 @@
 package glue_test
 
 import (
+	"errors"
 	"glue"
 	"math/rand"
 	"runtime"
@@ -223,3 +224,46 @@ func TestDeregConv(t *testing.T) {
 	assert.Equal(t, -1, a.A)
 
 }
+
+func TestRegConvAfterGlue(t *testing.T) {
+	type Foo struct {
+		A int
+	}
+	type Bar struct {
+		A string
+	}
+	a := &Foo{A: -1}
+	b := &Bar{A: "1023"}
+
+	err := glue.Glue(a, b, glue.DoStrict())
+	assert.ErrorIs(t, err, glue.ErrMissingConverter)
+
+	// registering a converter takes effect on the type pair seen before.
+	glue.MustRegConv(int(0), "", atoiConv)
+	defer glue.DeregConv(int(0), "")
+	err = glue.Glue(a, b, glue.DoStrict())
+	assert.NoError(t, err)
+	assert.Equal(t, 1023, a.A)
+}
+
+func TestConvNilInterface(t *testing.T) {
+	type Foo struct {
+		Err error
+	}
+	type Bar struct {
+		Err string
+	}
+	strToErr := func(s string) error {
+		if s == "" {
+			return nil
+		}
+		return errors.New(s)
+	}
+	glue.NewMapping(Foo{}, Bar{}).Convert("Err", strToErr).MustRegister()
+	defer glue.DeregMapping(Foo{}, Bar{})
+
+	a := &Foo{Err: errors.New("stale")}
+	err := glue.Glue(a, &Bar{})
+	assert.NoError(t, err)
+	assert.Nil(t, a.Err)
+}
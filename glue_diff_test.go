@@ -0,0 +1,126 @@
+package glue_test
+
+import (
+	"glue"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	type (
+		Audit struct {
+			Rev int
+		}
+		Entity struct {
+			ID   int
+			Name string
+			Age  int
+			Tags []string
+			Mail string `glue:"Email"`
+			*Audit
+		}
+		Update struct {
+			ID    int
+			Name  string
+			Age   string
+			Tags  []string
+			Email string
+			Rev   int
+		}
+	)
+	glue.MustRegConv(int(0), "", atoiConv)
+	defer glue.DeregConv(int(0), "")
+
+	e := &Entity{ID: 1, Name: "alice", Age: 20, Tags: []string{"a"}, Mail: "a@x"}
+	u := &Update{ID: 1, Name: "bob", Age: "20", Tags: []string{"a", "b"}, Email: "a@x", Rev: 2}
+
+	changes, err := glue.Diff(e, u)
+	assert.NoError(t, err)
+	assert.Equal(t, []glue.Change{
+		{Path: "Name", Old: "alice", New: "bob"},
+		{Path: "Tags", Old: []string{"a"}, New: []string{"a", "b"}},
+	}, changes)
+	assert.Equal(t, "Name: alice -> bob", changes[0].String())
+
+	// a field behind a nil pointer is changed from zero value.
+	changes, err = glue.Diff(e, u, glue.DoFavorSource())
+	assert.NoError(t, err)
+	assert.Equal(t, []glue.Change{
+		{Path: "Name", Old: "alice", New: "bob"},
+		{Path: "Tags", Old: []string{"a"}, New: []string{"a", "b"}},
+		{Path: "Audit.Rev", Old: 0, New: 2},
+	}, changes)
+
+	// dst is untouched.
+	assert.Equal(t, "alice", e.Name)
+	assert.Nil(t, e.Audit)
+
+	assert.NoError(t, glue.Glue(e, u, glue.DoFavorSource()))
+	changes, err = glue.Diff(e, u, glue.DoFavorSource())
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestDiffOptions(t *testing.T) {
+	type Foo struct {
+		A int
+		B int
+	}
+	type Bar struct {
+		A int
+		C int
+	}
+	f := &Foo{A: 1}
+
+	changes, err := glue.Diff(f, &Bar{A: 2})
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+
+	_, err = glue.Diff(f, &Bar{A: 2}, glue.DoStrict())
+	assert.ErrorIs(t, err, glue.ErrUnsatisfiedField)
+
+	changes, err = glue.Diff(f, &Bar{A: 1, C: 3}, glue.DoFavorSource())
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	_, err = glue.Diff(Foo{}, &Bar{})
+	assert.Equal(t, glue.ErrNotPtrToStruct, err)
+}
+
+func TestEqual(t *testing.T) {
+	type Model struct {
+		ID   int
+		Age  string
+		Note string
+	}
+	type DTO struct {
+		ID  int
+		Age int
+	}
+	glue.MustRegConv(int(0), "", atoiConv)
+	defer glue.DeregConv(int(0), "")
+
+	m := &Model{ID: 1, Age: "20", Note: "not matched"}
+	ok, path, err := glue.Equal(&DTO{ID: 1, Age: 20}, m)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Empty(t, path)
+
+	ok, path, err = glue.Equal(&DTO{ID: 1, Age: 21}, m)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, "Age", path)
+
+	// the first mismatch in the order of the plan.
+	ok, path, err = glue.Equal(&DTO{ID: 2, Age: 21}, m)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, "ID", path)
+
+	_, _, err = glue.Equal(&DTO{}, m, glue.DoFavorSource(), glue.DoStrict())
+	assert.ErrorIs(t, err, glue.ErrUnsatisfiedField)
+
+	_, _, err = glue.Equal(DTO{}, m)
+	assert.Equal(t, glue.ErrNotPtrToStruct, err)
+}
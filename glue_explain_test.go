@@ -0,0 +1,150 @@
+package glue_test
+
+import (
+	"glue"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func atoiConv(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func TestExplain(t *testing.T) {
+	type (
+		Emb struct {
+			C int
+		}
+		Foo struct {
+			A int
+			B int `glue:"X"`
+			C int
+			D float32
+			E int `glue:"-"`
+			f int
+			G int
+			H int
+		}
+		Bar struct {
+			A int
+			X int
+			Emb
+			D string
+			E int
+			H string
+		}
+	)
+	err := glue.RegConv(int(0), "", atoiConv)
+	assert.NoError(t, err)
+	defer glue.DeregConv(int(0), "")
+
+	report, err := glue.Explain(&Foo{}, &Bar{})
+	assert.NoError(t, err)
+	assert.Len(t, report.Fields, 8)
+
+	byField := make(map[string]glue.FieldReport)
+	for _, fr := range report.Fields {
+		byField[fr.Field] = fr
+	}
+	assert.True(t, byField["A"].Copied)
+	assert.Equal(t, "X", byField["B"].Src)
+	assert.Equal(t, "Emb.C", byField["C"].Src)
+	assert.False(t, byField["D"].Copied)
+	assert.ErrorIs(t, byField["D"].Err, glue.ErrMissingConverter)
+	assert.Contains(t, byField["D"].Reason, "no converter")
+	assert.Equal(t, "ignored by tag", byField["E"].Reason)
+	assert.Equal(t, "unexported", byField["f"].Reason)
+	assert.ErrorIs(t, byField["G"].Err, glue.ErrUnsatisfiedField)
+	assert.True(t, byField["H"].Copied)
+	assert.Contains(t, byField["H"].Converter, "atoiConv")
+
+	s := report.String()
+	assert.Contains(t, s, "C  <- Emb.C")
+	assert.Contains(t, s, "f  skipped: unexported")
+}
+
+func TestExplainMapping(t *testing.T) {
+	type Foo struct {
+		A int
+		B int
+		C int
+	}
+	type Bar struct {
+		X struct{ Y int }
+		C int
+	}
+	glue.NewMapping(Foo{}, Bar{}).
+		Field("A", "X.Y").
+		Ignore("B").
+		MustRegister()
+	defer glue.DeregMapping(Foo{}, Bar{})
+
+	report, err := glue.Explain(&Foo{}, &Bar{})
+	assert.NoError(t, err)
+	assert.Equal(t, []glue.FieldReport{
+		{Field: "A", Dst: "A", Src: "X.Y", Copied: true},
+		{Field: "B", Reason: "ignored by mapping"},
+		{Field: "C", Dst: "C", Src: "C", Copied: true},
+	}, report.Fields)
+
+	report, err = glue.Explain(&Foo{}, &Bar{}, glue.DoFavorSource())
+	assert.NoError(t, err)
+	assert.Contains(t, report.String(), "X.Y  -> A")
+
+	_, err = glue.Explain(1, &Bar{})
+	assert.Equal(t, glue.ErrNotPtrToStruct, err)
+}
+
+func TestExplainMasked(t *testing.T) {
+	type (
+		Addr struct{ City, Street string }
+		U    struct {
+			Name  string
+			Email string
+			Addr  Addr
+		}
+	)
+	report, err := glue.Explain(&U{}, &U{}, glue.DoOnly("Name", "Addr.City"))
+	assert.NoError(t, err)
+	fields := make([]string, len(report.Fields))
+	byField := make(map[string]glue.FieldReport)
+	for i, fr := range report.Fields {
+		fields[i] = fr.Field
+		byField[fr.Field] = fr
+	}
+	assert.Equal(t, []string{"Name", "Email", "Addr", "Addr.City"}, fields)
+	assert.True(t, byField["Name"].Copied)
+	assert.Equal(t, "masked out", byField["Email"].Reason)
+	assert.True(t, byField["Addr"].Partial)
+	assert.Empty(t, byField["Addr"].Reason)
+	assert.True(t, byField["Addr.City"].Copied)
+
+	s := report.String()
+	assert.Contains(t, s, "Email      skipped: masked out")
+	assert.Contains(t, s, "Addr       partially copied")
+	assert.Contains(t, s, "Addr.City  <- Addr.City")
+}
+
+func TestExplainGroups(t *testing.T) {
+	type (
+		U struct {
+			Name, Email, Secret string
+		}
+		View struct {
+			Name   string
+			Email  string `glue:",groups=admin"`
+			Secret string `glue:"-"`
+		}
+	)
+	report, err := glue.Explain(&View{}, &U{})
+	assert.NoError(t, err)
+	assert.Equal(t, "not in active groups", report.Fields[1].Reason)
+	assert.Equal(t, "ignored by tag", report.Fields[2].Reason)
+
+	report, err = glue.Explain(&View{}, &U{}, glue.DoGroups("admin"))
+	assert.NoError(t, err)
+	assert.True(t, report.Fields[1].Copied)
+}
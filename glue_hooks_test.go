@@ -0,0 +1,87 @@
+package glue_test
+
+import (
+	"errors"
+	"glue"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errEmptyEmail = errors.New("empty email")
+
+type hUser struct {
+	Email string
+	calls []string
+}
+
+func (u *hUser) BeforeGlue(src interface{}) error {
+	u.calls = append(u.calls, "BeforeGlue")
+	return nil
+}
+
+func (u *hUser) AfterGlue(src interface{}) error {
+	u.calls = append(u.calls, "AfterGlue")
+	u.Email = strings.ToLower(strings.TrimSpace(u.Email))
+	if u.Email == "" {
+		return errEmptyEmail
+	}
+	return nil
+}
+
+type hForm struct {
+	Email string
+	calls []string
+}
+
+func (f *hForm) BeforeGlueOut(dst interface{}) error {
+	f.calls = append(f.calls, "BeforeGlueOut")
+	if _, ok := dst.(*hUser); !ok {
+		return errors.New("unexpected destination")
+	}
+	return nil
+}
+
+func (f *hForm) AfterGlueOut(dst interface{}) error {
+	f.calls = append(f.calls, "AfterGlueOut")
+	return nil
+}
+
+func TestHooks(t *testing.T) {
+	u := &hUser{}
+	f := &hForm{Email: "  Glue@Example.COM "}
+
+	err := glue.Glue(u, f)
+	assert.NoError(t, err)
+	assert.Equal(t, "glue@example.com", u.Email)
+	assert.Equal(t, []string{"BeforeGlue", "AfterGlue"}, u.calls)
+	assert.Empty(t, f.calls)
+
+	u.calls = nil
+	err = glue.Glue(u, f, glue.DoFavorSource())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"BeforeGlue", "AfterGlue"}, u.calls)
+	assert.Equal(t, []string{"BeforeGlueOut", "AfterGlueOut"}, f.calls)
+}
+
+func TestHooksFail(t *testing.T) {
+	var hookErr *glue.HookError
+
+	u := &hUser{}
+	err := glue.Glue(u, &hForm{Email: " "})
+	assert.ErrorIs(t, err, errEmptyEmail)
+	assert.ErrorIs(t, err, glue.ErrHook)
+	assert.ErrorIs(t, err, glue.ErrGlue)
+	assert.True(t, errors.As(err, &hookErr))
+	assert.Equal(t, "AfterGlue", hookErr.Hook)
+
+	type Other struct {
+		Email string
+	}
+	f := &hForm{Email: "a@b.c"}
+	o := &Other{}
+	err = glue.Glue(o, f, glue.DoFavorSource())
+	assert.ErrorIs(t, err, glue.ErrHook)
+	assert.Equal(t, "", o.Email)
+}
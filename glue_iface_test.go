@@ -0,0 +1,212 @@
+package glue_test
+
+import (
+	"errors"
+	"glue"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Shape interface {
+	Area() float64
+}
+
+type Circle struct {
+	R float64
+}
+
+func (c Circle) Area() float64 {
+	return math.Pi * c.R * c.R
+}
+
+type Square struct {
+	L float64
+}
+
+func (s *Square) Area() float64 {
+	return s.L * s.L
+}
+
+func TestGlueIntoInterface(t *testing.T) {
+	type Foo struct {
+		A Shape
+		B Shape
+		C interface{}
+		D Shape
+	}
+	type Bar struct {
+		A Circle
+		B *Square
+		C int
+		D Square // only *Square implements Shape.
+	}
+	f := &Foo{}
+	b := &Bar{A: Circle{R: 1}, B: &Square{L: 2}, C: 1024}
+
+	err := glue.Glue(f, b)
+	assert.NoError(t, err)
+	assert.Equal(t, Circle{R: 1}, f.A)
+	assert.Same(t, b.B, f.B)
+	assert.Equal(t, 1024, f.C)
+	assert.Nil(t, f.D)
+
+	err = glue.Check(&Foo{}, &Bar{})
+	assert.ErrorIs(t, err, glue.ErrMissingConverter)
+}
+
+func TestGlueOutOfInterface(t *testing.T) {
+	type Foo struct {
+		A int
+		B Circle
+		C float32
+		D Shape
+	}
+	type Bar struct {
+		A interface{}
+		B Shape
+		C interface{}
+		D interface{}
+	}
+	err := glue.RegConv(float32(0), uint8(0), func(n uint8) float32 {
+		return float32(n)
+	})
+	assert.NoError(t, err)
+	defer glue.DeregConv(float32(0), uint8(0))
+
+	f := &Foo{}
+	b := &Bar{A: 1023, B: Circle{R: 2}, C: uint8(255), D: &Square{L: 1}}
+
+	assert.NoError(t, glue.Check(&Foo{}, &Bar{}, glue.DoStrict()))
+	err = glue.Glue(f, b, glue.DoStrict())
+	assert.NoError(t, err)
+	assert.Equal(t, 1023, f.A)
+	assert.Equal(t, Circle{R: 2}, f.B)
+	assert.Equal(t, float32(255), f.C)
+	assert.Equal(t, b.D, f.D)
+
+	// the dynamic value cannot be converted.
+	b.A = "1023"
+	f.A = -1
+	err = glue.Glue(f, b)
+	assert.NoError(t, err)
+	assert.Equal(t, -1, f.A)
+	err = glue.Glue(f, b, glue.DoStrict())
+	assert.ErrorIs(t, err, glue.ErrMissingConverter)
+
+	// nil interface.
+	b.A = nil
+	err = glue.Glue(f, b)
+	assert.NoError(t, err)
+	assert.Equal(t, -1, f.A)
+	err = glue.Glue(f, b, glue.DoStrict())
+	assert.ErrorIs(t, err, glue.ErrUnsatisfiedField)
+	err = glue.Glue(f, b, glue.DoNilAsZero())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, f.A)
+}
+
+func TestGlueNilInterface(t *testing.T) {
+	type Foo struct {
+		A Shape
+		B interface{}
+	}
+	f := &Foo{A: Circle{}, B: 1}
+
+	err := glue.Glue(f, &Foo{})
+	assert.NoError(t, err)
+	assert.Nil(t, f.A)
+	assert.Nil(t, f.B)
+}
+
+func TestGlueConcrete(t *testing.T) {
+	type (
+		SquareDTO struct{ L float64 }
+		Foo       struct {
+			A Shape
+			B Shape
+			C interface{}
+			D Shape
+		}
+		Bar struct {
+			A SquareDTO
+			B *SquareDTO
+			C SquareDTO
+			D Circle // implements Shape but is of another concrete type.
+		}
+	)
+	assert.Equal(t, glue.ErrNotInterface, glue.RegConcrete(Circle{}, Circle{}))
+	assert.Equal(t, glue.ErrNotStruct, glue.RegConcrete((*Shape)(nil), 1))
+	assert.Equal(t, glue.ErrNotImplemented, glue.RegConcrete((*Shape)(nil), Square{}))
+
+	// not glued without the registry.
+	assert.ErrorIs(t, glue.Check(&Foo{}, &Bar{}), glue.ErrMissingConverter)
+
+	glue.MustRegConcrete((*Shape)(nil), &Square{})
+	defer glue.DeregConcrete((*Shape)(nil))
+	glue.MustRegConcrete((*interface{})(nil), Circle{})
+	defer glue.DeregConcrete((*interface{})(nil))
+
+	f := &Foo{B: Circle{}}
+	err := glue.Glue(f, &Bar{A: SquareDTO{L: 2}, C: SquareDTO{L: 3}, D: Circle{R: 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, &Square{L: 2}, f.A)
+	// a nil pointer is a nil interface.
+	assert.Nil(t, f.B)
+	// the fields of the concrete type are matched by name.
+	assert.Equal(t, Circle{}, f.C)
+	assert.Equal(t, &Square{}, f.D)
+
+	err = glue.Glue(f, &Bar{B: &SquareDTO{L: 4}}, glue.DoOnly("B"))
+	assert.NoError(t, err)
+	assert.Equal(t, &Square{L: 4}, f.B)
+
+	// the source of the concrete type is assigned as is.
+	type Baz struct{ A *Square }
+	s := &Square{L: 5}
+	assert.NoError(t, glue.Glue(f, &Baz{A: s}))
+	assert.Same(t, s, f.A)
+
+	err = glue.Glue(f, &Bar{}, glue.DoStrict())
+	assert.ErrorIs(t, err, glue.ErrUnsatisfiedField)
+	var fe *glue.FieldError
+	assert.ErrorAs(t, err, &fe)
+	assert.Equal(t, "C", fe.Path)
+}
+
+type hexagon struct {
+	L     float64
+	calls []string
+}
+
+func (h *hexagon) Area() float64 {
+	return 3 * math.Sqrt(3) / 2 * h.L * h.L
+}
+
+func (h *hexagon) AfterGlue(src interface{}) error {
+	h.calls = append(h.calls, "AfterGlue")
+	if h.L < 0 {
+		return errors.New("negative length")
+	}
+	return nil
+}
+
+func TestGlueConcreteHooks(t *testing.T) {
+	type (
+		HexagonDTO struct{ L float64 }
+		Foo        struct{ A Shape }
+		Bar        struct{ A HexagonDTO }
+	)
+	glue.MustRegConcrete((*Shape)(nil), &hexagon{})
+	defer glue.DeregConcrete((*Shape)(nil))
+
+	f := &Foo{}
+	assert.NoError(t, glue.Glue(f, &Bar{A: HexagonDTO{L: 1}}))
+	assert.Equal(t, &hexagon{L: 1, calls: []string{"AfterGlue"}}, f.A)
+
+	err := glue.Glue(f, &Bar{A: HexagonDTO{L: -1}})
+	var fe *glue.FieldError
+	assert.ErrorAs(t, err, &fe)
+	assert.Equal(t, "A", fe.Path)
+}
@@ -0,0 +1,206 @@
+package glue_test
+
+import (
+	"glue"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMappingBasic(t *testing.T) {
+	type (
+		Owner struct {
+			ID string
+		}
+		Meta struct {
+			Owner *Owner
+		}
+		Record struct {
+			Name      string
+			Meta      Meta
+			Internal  string
+			CreatedAt int64
+		}
+		View struct {
+			Name      string `glue:"Internal"` // tags are not consulted.
+			OwnerID   string
+			Internal  string
+			CreatedAt time.Time
+		}
+	)
+	tsToTime := func(ts int64) time.Time {
+		return time.Unix(ts, 0).UTC()
+	}
+	err := glue.NewMapping(View{}, Record{}).
+		Field("OwnerID", "Meta.Owner.ID").
+		Ignore("Internal").
+		Convert("CreatedAt", tsToTime).
+		Register()
+	assert.NoError(t, err)
+	defer glue.DeregMapping(View{}, Record{})
+
+	r := &Record{
+		Name:      "glue",
+		Meta:      Meta{Owner: &Owner{ID: "o-1"}},
+		Internal:  "secret",
+		CreatedAt: 1024,
+	}
+	v := &View{Internal: "untouched"}
+	err = glue.Glue(v, r, glue.DoStrict())
+	assert.NoError(t, err)
+	assert.Equal(t, "glue", v.Name)
+	assert.Equal(t, "o-1", v.OwnerID)
+	assert.Equal(t, "untouched", v.Internal)
+	assert.Equal(t, tsToTime(1024), v.CreatedAt)
+
+	// nil pointer on the way of source path.
+	r.Meta.Owner = nil
+	v = &View{OwnerID: "untouched"}
+	err = glue.Glue(v, r)
+	assert.NoError(t, err)
+	assert.Equal(t, "untouched", v.OwnerID)
+}
+
+func TestMappingAllocDst(t *testing.T) {
+	type (
+		Owner struct {
+			ID string
+		}
+		Foo struct {
+			Owner *Owner
+		}
+		Bar struct {
+			OwnerID string
+		}
+	)
+	err := glue.NewMapping(&Foo{}, &Bar{}).
+		Field("Owner.ID", "OwnerID").
+		Register()
+	assert.NoError(t, err)
+	defer glue.DeregMapping(&Foo{}, &Bar{})
+
+	f := &Foo{}
+	err = glue.Glue(f, &Bar{OwnerID: "o-2"})
+	assert.NoError(t, err)
+	assert.Equal(t, "o-2", f.Owner.ID)
+}
+
+func TestMappingFavorSource(t *testing.T) {
+	type Foo struct {
+		A int
+		B int
+	}
+	type Bar struct {
+		C int
+		D int
+	}
+	glue.NewMapping(Foo{}, Bar{}).
+		Field("A", "C").
+		Ignore("B").
+		MustRegister()
+	defer glue.DeregMapping(Foo{}, Bar{})
+
+	f := &Foo{B: -1}
+	err := glue.Glue(f, &Bar{C: 1, D: 2}, glue.DoFavorSource())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, f.A)
+	assert.Equal(t, -1, f.B)
+
+	err = glue.Glue(f, &Bar{C: 1, D: 2}, glue.DoFavorSource(), glue.DoStrict())
+	assert.ErrorIs(t, err, glue.ErrUnsatisfiedField)
+}
+
+func TestMappingInvalid(t *testing.T) {
+	type Foo struct {
+		A int
+		b int
+	}
+	type Bar struct {
+		A string
+	}
+	var err error
+
+	err = glue.NewMapping(Foo{}, Bar{}).Field("A", "Missing").Register()
+	assert.ErrorIs(t, err, glue.ErrUnknownField)
+	err = glue.NewMapping(Foo{}, Bar{}).Field("b", "A").Register()
+	assert.ErrorIs(t, err, glue.ErrUnknownField)
+	err = glue.NewMapping(Foo{}, Bar{}).Ignore("A.B").Register()
+	assert.ErrorIs(t, err, glue.ErrUnknownField)
+	err = glue.NewMapping(Foo{}, 0).Register()
+	assert.ErrorIs(t, err, glue.ErrNotStruct)
+	err = glue.NewMapping(Foo{}, Bar{}).Convert("A", 0).Register()
+	assert.ErrorIs(t, err, glue.ErrNotFunction)
+	err = glue.NewMapping(Foo{}, Bar{}).
+		Convert("A", func(int) string { return "" }).
+		Register()
+	assert.ErrorIs(t, err, glue.ErrIncompatSignature)
+
+	assert.Panics(t, func() {
+		glue.NewMapping(Foo{}, Bar{}).Field("A", "B").MustRegister()
+	})
+}
+
+func TestMappingRegisterCopy(t *testing.T) {
+	type (
+		Src  struct{ A, B int }
+		Dest struct{ X, B int }
+	)
+	m := glue.NewMapping(Dest{}, Src{}).Field("X", "A")
+	assert.NoError(t, m.Register())
+	defer glue.DeregMapping(Dest{}, Src{})
+
+	// the registered mapping is not changed by the builder.
+	m.Field("X", "B").Ignore("B")
+	d := &Dest{}
+	assert.NoError(t, glue.Glue(d, &Src{A: 1, B: 2}))
+	assert.Equal(t, &Dest{X: 1, B: 2}, d)
+
+	assert.NoError(t, m.Register())
+	d = &Dest{}
+	assert.NoError(t, glue.Glue(d, &Src{A: 1, B: 2}))
+	assert.Equal(t, &Dest{X: 2}, d)
+}
+
+func TestMappingNestedStrict(t *testing.T) {
+	type (
+		Owner struct {
+			ID string
+		}
+		Foo struct {
+			Name  string
+			Owner *Owner
+		}
+		Bar struct {
+			Name    string
+			OwnerID string
+		}
+	)
+	glue.NewMapping(&Foo{}, &Bar{}).
+		Field("Owner.ID", "OwnerID").
+		MustRegister()
+	defer glue.DeregMapping(&Foo{}, &Bar{})
+	glue.NewMapping(&Bar{}, &Foo{}).
+		Field("OwnerID", "Owner.ID").
+		MustRegister()
+	defer glue.DeregMapping(&Bar{}, &Foo{})
+
+	// `Owner` is covered by the mapping of its field.
+	f := &Foo{}
+	assert.NoError(t, glue.Glue(f, &Bar{Name: "n", OwnerID: "o-1"}, glue.DoStrict()))
+	assert.Equal(t, &Foo{Name: "n", Owner: &Owner{ID: "o-1"}}, f)
+	assert.NoError(t, glue.Check(&Foo{}, &Bar{}, glue.DoStrict()))
+
+	b := &Bar{}
+	err := glue.Glue(b, &Foo{Name: "n", Owner: &Owner{ID: "o-2"}},
+		glue.DoFavorSource(), glue.DoStrict())
+	assert.NoError(t, err)
+	assert.Equal(t, &Bar{Name: "n", OwnerID: "o-2"}, b)
+
+	report, err := glue.Explain(&Foo{}, &Bar{})
+	assert.NoError(t, err)
+	s := report.String()
+	assert.Contains(t, s, "Owner     partially copied")
+	assert.Contains(t, s, "Owner.ID  <- OwnerID")
+	assert.NotContains(t, s, "skipped")
+}
@@ -0,0 +1,211 @@
+package glue_test
+
+import (
+	"glue"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type maskAddress struct {
+	City   string
+	Street string
+}
+
+type maskUser struct {
+	Name    string
+	Email   string
+	Age     int
+	Address *maskAddress
+}
+
+type maskUserDTO struct {
+	Name    string
+	Email   string
+	Age     int
+	Address maskAddress
+}
+
+func maskSource() *maskUserDTO {
+	return &maskUserDTO{
+		Name:    "name",
+		Email:   "mail@example.com",
+		Age:     42,
+		Address: maskAddress{City: "city", Street: "street"},
+	}
+}
+
+func TestOnly(t *testing.T) {
+	u := &maskUser{Name: "old", Age: 1}
+	err := glue.Glue(u, maskSource(), glue.DoOnly("Name", "Address.City"))
+	assert.NoError(t, err)
+	assert.Equal(t, &maskUser{
+		Name:    "name",
+		Age:     1,
+		Address: &maskAddress{City: "city"},
+	}, u)
+
+	// the paths add up.
+	u = &maskUser{}
+	err = glue.Glue(u, maskSource(),
+		glue.DoOnly("Name"), glue.DoOnly("Address.City", "Address.Street"))
+	assert.NoError(t, err)
+	assert.Equal(t, &maskUser{
+		Name:    "name",
+		Address: &maskAddress{City: "city", Street: "street"},
+	}, u)
+
+	// no path means no field.
+	u = &maskUser{Name: "old"}
+	assert.NoError(t, glue.Glue(u, maskSource(), glue.DoOnly()))
+	assert.Equal(t, &maskUser{Name: "old"}, u)
+}
+
+func TestExcept(t *testing.T) {
+	u := &maskUser{Email: "old", Address: &maskAddress{Street: "old"}}
+	err := glue.Glue(u, maskSource(), glue.DoExcept("Email", "Address.Street"))
+	assert.NoError(t, err)
+	assert.Equal(t, &maskUser{
+		Name:    "name",
+		Email:   "old",
+		Age:     42,
+		Address: &maskAddress{City: "city", Street: "old"},
+	}, u)
+
+	u = &maskUser{}
+	err = glue.Glue(u, maskSource(),
+		glue.DoOnly("Name", "Address"), glue.DoExcept("Address.City"))
+	assert.NoError(t, err)
+	assert.Equal(t, &maskUser{
+		Name:    "name",
+		Address: &maskAddress{Street: "street"},
+	}, u)
+}
+
+func TestMaskUnknownPath(t *testing.T) {
+	u := &maskUser{}
+	err := glue.Glue(u, maskSource(), glue.DoOnly("Name", "Phone"))
+	assert.ErrorIs(t, err, glue.ErrUnknownField)
+	assert.Contains(t, err.Error(), `"Phone"`)
+
+	err = glue.Glue(u, maskSource(), glue.DoExcept("Address.Zip"))
+	assert.ErrorIs(t, err, glue.ErrUnknownField)
+
+	// the paths are of the destination.
+	err = glue.Glue(&struct{ Title string }{}, maskSource(), glue.DoOnly("Name"))
+	assert.ErrorIs(t, err, glue.ErrUnknownField)
+}
+
+func TestMaskStrict(t *testing.T) {
+	type Partial struct {
+		Name  string
+		Phone string
+	}
+	p := &Partial{}
+	assert.Error(t, glue.Glue(p, maskSource(), glue.DoStrict()))
+	// the unsatisfied field is masked out.
+	assert.NoError(t, glue.Glue(p, maskSource(), glue.DoStrict(), glue.DoExcept("Phone")))
+	assert.Equal(t, "name", p.Name)
+	assert.Error(t, glue.Glue(p, maskSource(), glue.DoStrict(), glue.DoOnly("Phone")))
+}
+
+func TestMaskPartialConverted(t *testing.T) {
+	type (
+		Src  struct{ Address string }
+		Dest struct{ Address maskAddress }
+	)
+	glue.MustRegConv(maskAddress{}, "", func(s string) maskAddress {
+		return maskAddress{City: s}
+	})
+	defer glue.DeregConv(maskAddress{}, "")
+
+	d := &Dest{}
+	assert.NoError(t, glue.Glue(d, &Src{Address: "city"}, glue.DoOnly("Address")))
+	assert.Equal(t, "city", d.Address.City)
+
+	err := glue.Glue(d, &Src{Address: "city"}, glue.DoStrict(), glue.DoOnly("Address.City"))
+	assert.ErrorIs(t, err, glue.ErrUnsatisfiedField)
+}
+
+func TestMaskPromoted(t *testing.T) {
+	type (
+		Base struct{ ID int }
+		Dest struct {
+			Base
+			Name string
+		}
+		Src struct {
+			ID   int
+			Name string
+		}
+	)
+	// a promoted field is selected by either path.
+	for _, path := range []string{"ID", "Base.ID"} {
+		d := &Dest{}
+		err := glue.Glue(d, &Src{ID: 1, Name: "name"},
+			glue.DoFavorSource(), glue.DoOnly(path))
+		assert.NoError(t, err)
+		assert.Equal(t, &Dest{Base: Base{ID: 1}}, d)
+	}
+}
+
+func TestMaskOptions(t *testing.T) {
+	var users []maskUser
+	err := glue.GlueSlice(&users, []maskUserDTO{*maskSource()}, glue.DoOnly("Age"))
+	assert.NoError(t, err)
+	assert.Equal(t, []maskUser{{Age: 42}}, users)
+
+	changes, err := glue.Diff(&maskUser{}, maskSource(), glue.DoOnly("Name", "Address.City"))
+	assert.NoError(t, err)
+	paths := make([]string, len(changes))
+	for i, c := range changes {
+		paths[i] = c.Path
+	}
+	assert.Equal(t, []string{"Name", "Address.City"}, paths)
+
+	// the masks are of the destination in reverse as well.
+	d := &maskUserDTO{}
+	err = glue.GlueReverse(d, &maskUser{Name: "name", Age: 42}, glue.DoExcept("Age"))
+	assert.NoError(t, err)
+	assert.Equal(t, &maskUserDTO{Name: "name"}, d)
+}
+
+func TestFieldMask(t *testing.T) {
+	type (
+		Profile struct {
+			DisplayName string
+			AvatarURL   string
+		}
+		User struct {
+			UserID  int
+			Handle  string `protobuf:"bytes,2,opt,name=nick_name,proto3"`
+			Profile *Profile
+		}
+	)
+	src := &User{
+		UserID:  1,
+		Handle:  "nick",
+		Profile: &Profile{DisplayName: "name", AvatarURL: "url"},
+	}
+
+	u := &User{}
+	err := glue.Glue(u, src, glue.DoFieldMask("user_id", "profile.display_name"))
+	assert.NoError(t, err)
+	assert.Equal(t, &User{UserID: 1, Profile: &Profile{DisplayName: "name"}}, u)
+
+	// the tag name takes precedence, the paths add up with `DoOnly`.
+	u = &User{}
+	err = glue.Glue(u, src, glue.DoFieldMask("nick_name"), glue.DoOnly("Profile.AvatarURL"))
+	assert.NoError(t, err)
+	assert.Equal(t, &User{Handle: "nick", Profile: &Profile{AvatarURL: "url"}}, u)
+
+	for _, path := range []string{"nick", "profile.display", "user_id.value", "", "profile."} {
+		err = glue.Glue(&User{}, src, glue.DoFieldMask(path))
+		assert.ErrorIs(t, err, glue.ErrUnknownField, path)
+	}
+
+	// no path means no field.
+	u = &User{}
+	assert.NoError(t, glue.Glue(u, src, glue.DoFieldMask()))
+	assert.Equal(t, &User{}, u)
+}
@@ -0,0 +1,90 @@
+package glue_test
+
+import (
+	"glue"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeTag(t *testing.T) {
+	type (
+		Entity struct {
+			Tags   []string          `glue:",merge=append"`
+			Labels map[string]string `glue:",merge=union"`
+			Owners map[string]int    `glue:",merge=union-keep"`
+			Notes  []string          `glue:",merge=replace"`
+		}
+		Update struct {
+			Tags   []string
+			Labels map[string]string
+			Owners map[string]int
+			Notes  []string
+		}
+	)
+	tags := []string{"a"}
+	e := &Entity{
+		Tags:   tags,
+		Labels: map[string]string{"env": "dev", "team": "x"},
+		Owners: map[string]int{"alice": 1},
+		Notes:  []string{"old"},
+	}
+	u := &Update{
+		Tags:   []string{"b", "c"},
+		Labels: map[string]string{"env": "prod"},
+		Owners: map[string]int{"alice": 2, "bob": 3},
+		Notes:  []string{"new"},
+	}
+	assert.NoError(t, glue.Glue(e, u))
+	assert.Equal(t, &Entity{
+		Tags:   []string{"a", "b", "c"},
+		Labels: map[string]string{"env": "prod", "team": "x"},
+		Owners: map[string]int{"alice": 1, "bob": 3},
+		Notes:  []string{"new"},
+	}, e)
+	// neither side is modified in place.
+	assert.Equal(t, []string{"a"}, tags)
+	assert.Equal(t, map[string]int{"alice": 2, "bob": 3}, u.Owners)
+
+	// empty sources leave the fields as they are.
+	assert.NoError(t, glue.Glue(e, &Update{}))
+	assert.Equal(t, []string{"a", "b", "c"}, e.Tags)
+	assert.Equal(t, map[string]int{"alice": 1, "bob": 3}, e.Owners)
+	assert.Nil(t, e.Notes)
+}
+
+func TestMergeOption(t *testing.T) {
+	type Foo struct {
+		S []int
+		M map[string]int
+		N []int `glue:",merge=replace"`
+	}
+	f := &Foo{S: []int{1}, M: map[string]int{"a": 1}, N: []int{1}}
+	b := &Foo{S: []int{2}, M: map[string]int{"a": 2}, N: []int{2}}
+
+	err := glue.Glue(f, b, glue.DoMerge(glue.MergeAppend, glue.MergeUnionKeep))
+	assert.NoError(t, err)
+	assert.Equal(t, &Foo{S: []int{1, 2}, M: map[string]int{"a": 1}, N: []int{2}}, f)
+
+	err = glue.Glue(f, b, glue.DoMerge(glue.MergeUnion), glue.DoUnsafeCopy())
+	assert.NoError(t, err)
+	assert.Equal(t, &Foo{S: []int{2}, M: map[string]int{"a": 2}, N: []int{2}}, f)
+
+	changes, err := glue.Diff(f, b, glue.DoMerge(glue.MergeAppend))
+	assert.NoError(t, err)
+	assert.Equal(t, []glue.Change{{Path: "S", Old: []int{2}, New: []int{2, 2}}}, changes)
+}
+
+func TestMergeInvalid(t *testing.T) {
+	type Foo struct {
+		M map[string]int `glue:",merge=append"`
+	}
+	type Bar struct {
+		S []int `glue:",merge=concat"`
+	}
+	assert.Panics(t, func() {
+		_ = glue.Glue(&Foo{}, &Foo{})
+	})
+	assert.ErrorIs(t, glue.Check(&Foo{}, &Foo{}), glue.ErrInvalidTag)
+	assert.ErrorIs(t, glue.Check(&Bar{}, &Bar{}), glue.ErrInvalidTag)
+}
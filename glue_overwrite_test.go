@@ -0,0 +1,152 @@
+package glue_test
+
+import (
+	"glue"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type owAddr struct {
+	City string
+	Zip  string
+}
+
+type owConfig struct {
+	Name    string
+	Port    int
+	Tags    []string
+	Labels  map[string]string
+	Addr    owAddr
+	Timeout *int
+}
+
+func owDefaults() *owConfig {
+	timeout := 30
+	return &owConfig{
+		Name:    "default",
+		Port:    8080,
+		Tags:    []string{"default"},
+		Labels:  map[string]string{"env": "dev"},
+		Addr:    owAddr{City: "city", Zip: "000"},
+		Timeout: &timeout,
+	}
+}
+
+func TestOverwriteOnlyIfDstZero(t *testing.T) {
+	c := &owConfig{
+		Name:   "mine",
+		Tags:   []string{},
+		Labels: map[string]string{"env": "prod"},
+		Addr:   owAddr{City: "mine"},
+	}
+	defaults := owDefaults()
+	err := glue.Glue(c, defaults, glue.DoOverwrite(glue.OverwriteOnlyIfDstZero))
+	assert.NoError(t, err)
+	assert.Equal(t, &owConfig{
+		Name:   "mine",
+		Port:   8080,
+		Tags:   []string{"default"},
+		Labels: map[string]string{"env": "prod"},
+		// a nested struct is not zero as a whole.
+		Addr:    owAddr{City: "mine"},
+		Timeout: defaults.Timeout,
+	}, c)
+}
+
+func TestOverwriteOnlyIfSrcNonZero(t *testing.T) {
+	c := owDefaults()
+	patch := &owConfig{
+		Port:   9090,
+		Tags:   []string{},
+		Labels: map[string]string{"env": "prod"},
+		Addr:   owAddr{Zip: "111"},
+	}
+	err := glue.Glue(c, patch, glue.DoOverwrite(glue.OverwriteOnlyIfSrcNonZero))
+	assert.NoError(t, err)
+	assert.Equal(t, "default", c.Name)
+	assert.Equal(t, 9090, c.Port)
+	assert.Equal(t, []string{"default"}, c.Tags)
+	assert.Equal(t, map[string]string{"env": "prod"}, c.Labels)
+	assert.Equal(t, owAddr{Zip: "111"}, c.Addr)
+	assert.Equal(t, 30, *c.Timeout)
+
+	// the default policy and the plan shared with it are not affected.
+	c = owDefaults()
+	err = glue.Glue(c, patch, glue.DoOverwrite(glue.OverwriteAlways), glue.DoUnsafeCopy())
+	assert.NoError(t, err)
+	assert.Equal(t, patch, c)
+}
+
+func TestOverwriteDiff(t *testing.T) {
+	type Foo struct {
+		A int
+		B int
+	}
+	changes, err := glue.Diff(
+		&Foo{A: 1}, &Foo{A: 2, B: 3},
+		glue.DoOverwrite(glue.OverwriteOnlyIfDstZero),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []glue.Change{{Path: "B", Old: 0, New: 3}}, changes)
+}
+
+func TestOverwriteNoAlloc(t *testing.T) {
+	type (
+		OwEmb struct{ A int }
+		D     struct {
+			*OwEmb
+			C int
+		}
+		S struct{ A, C int }
+	)
+	skip := func(path string, dst, src reflect.Value) (reflect.Value, bool, error) {
+		return src, false, nil
+	}
+	for _, opt := range []glue.GlueOption{
+		glue.DoOverwrite(glue.OverwriteOnlyIfSrcNonZero),
+		glue.DoFieldHook(skip),
+	} {
+		// the embedded pointer is only allocated if a field behind it is written.
+		d := &D{}
+		assert.NoError(t, glue.Glue(d, &S{}, glue.DoFavorSource(), opt))
+		assert.Nil(t, d.OwEmb)
+		changes, err := glue.Diff(&D{}, &S{}, glue.DoFavorSource(), opt)
+		assert.NoError(t, err)
+		assert.Empty(t, changes)
+	}
+
+	d := &D{}
+	err := glue.Glue(d, &S{A: 1}, glue.DoFavorSource(),
+		glue.DoOverwrite(glue.OverwriteOnlyIfSrcNonZero))
+	assert.NoError(t, err)
+	assert.Equal(t, &D{OwEmb: &OwEmb{A: 1}}, d)
+}
+
+type owInner struct{ X int }
+
+type OwOuter struct{ *owInner }
+
+func TestGlueUnallocatable(t *testing.T) {
+	type (
+		D struct{ *OwOuter }
+		S struct{ X int }
+	)
+	// `owInner` would be nil behind the allocated `OwOuter`.
+	d := &D{}
+	assert.NoError(t, glue.Glue(d, &S{X: 1}, glue.DoFavorSource()))
+	assert.Nil(t, d.OwOuter)
+	changes, err := glue.Diff(&D{}, &S{X: 1}, glue.DoFavorSource())
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	err = glue.Glue(&D{}, &S{X: 1}, glue.DoFavorSource(), glue.DoStrict())
+	assert.ErrorIs(t, err, glue.ErrUnsatisfiedField)
+	_, err = glue.Diff(&D{}, &S{X: 1}, glue.DoFavorSource(), glue.DoStrict())
+	assert.ErrorIs(t, err, glue.ErrUnsatisfiedField)
+
+	d = &D{OwOuter: &OwOuter{}}
+	assert.NoError(t, glue.Glue(d, &S{X: 1}, glue.DoFavorSource()))
+	assert.Nil(t, d.owInner)
+}
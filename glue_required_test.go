@@ -0,0 +1,94 @@
+package glue_test
+
+import (
+	"errors"
+	"glue"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type reqUser struct {
+	Name  string
+	Email string
+	Tags  []string
+}
+
+func TestRequired(t *testing.T) {
+	type DTO struct {
+		Name  string `glue:",required"`
+		Phone string `glue:",required"`
+		Fax   string `glue:",required"`
+		Email string
+	}
+	// relaxed mode tolerates other fields without counterpart.
+	d := &DTO{}
+	err := glue.Glue(d, &reqUser{Name: "name", Email: "mail"})
+	assert.ErrorIs(t, err, glue.ErrRequiredField)
+	assert.ErrorIs(t, err, glue.ErrUnsatisfiedField)
+	assert.Equal(t, &DTO{}, d)
+
+	var re *glue.RequiredError
+	assert.True(t, errors.As(err, &re))
+	assert.Equal(t, []string{"Phone", "Fax"}, re.Missing)
+	assert.Contains(t, err.Error(), "no counterpart for Phone, Fax")
+
+	err = glue.Check(&DTO{}, &reqUser{})
+	assert.ErrorIs(t, err, glue.ErrRequiredField)
+
+	// satisfied in push mode as well.
+	type Contact struct {
+		Name  string `glue:",required"`
+		Email string
+	}
+	c := &Contact{}
+	assert.NoError(t, glue.Glue(c, &reqUser{Name: "name"}, glue.DoFavorSource()))
+	assert.Equal(t, "name", c.Name)
+}
+
+func TestNonZero(t *testing.T) {
+	type DTO struct {
+		Name  string   `glue:",nonzero"`
+		Email string   `glue:",required,nonzero"`
+		Tags  []string `glue:",nonzero"`
+	}
+	d := &DTO{}
+	assert.NoError(t, glue.Glue(d, &reqUser{Name: "name", Email: "mail", Tags: []string{"a"}}))
+
+	err := glue.Glue(&DTO{}, &reqUser{Name: "name", Tags: []string{}})
+	assert.ErrorIs(t, err, glue.ErrRequiredField)
+	assert.False(t, errors.Is(err, glue.ErrUnsatisfiedField))
+	var re *glue.RequiredError
+	assert.True(t, errors.As(err, &re))
+	assert.Equal(t, []string{"Email", "Tags"}, re.Zero)
+
+	// the fields are checked after gluing, a value kept in dst counts.
+	d = &DTO{Email: "kept", Tags: []string{"kept"}}
+	err = glue.Glue(d, &reqUser{Name: "name"},
+		glue.DoOverwrite(glue.OverwriteOnlyIfSrcNonZero))
+	assert.NoError(t, err)
+
+	var dtos []DTO
+	err = glue.GlueSlice(&dtos, []reqUser{{Name: "name", Email: "mail", Tags: []string{"a"}}, {}})
+	assert.ErrorIs(t, err, glue.ErrRequiredField)
+}
+
+func TestRequiredMasked(t *testing.T) {
+	type DTO struct {
+		Name  string `glue:",required"`
+		Phone string `glue:",required"`
+	}
+	// a mask does not lift the requirement.
+	err := glue.Glue(&DTO{}, &reqUser{}, glue.DoExcept("Phone"))
+	assert.ErrorIs(t, err, glue.ErrRequiredField)
+
+	type Patch struct {
+		Name  string
+		Email string `glue:",nonzero"`
+	}
+	// the field masked out is checked as it is in dst.
+	err = glue.Glue(&Patch{Email: "kept"}, &reqUser{}, glue.DoOnly("Name"))
+	assert.NoError(t, err)
+	err = glue.Glue(&Patch{}, &reqUser{Email: "mail"}, glue.DoOnly("Name"))
+	assert.ErrorIs(t, err, glue.ErrRequiredField)
+}
@@ -0,0 +1,105 @@
+package glue_test
+
+import (
+	"glue"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlueReverse(t *testing.T) {
+	type (
+		Model struct {
+			ID      int
+			Name    string
+			Created int64
+			Secret  string
+		}
+		DTO struct {
+			Key     int    `glue:"ID"`
+			Title   string `glue:"Name,transform=upper"`
+			Created time.Time
+		}
+	)
+	glue.MustRegTransform("upper", strings.ToUpper)
+	defer glue.DeregTransform("upper")
+	glue.MustRegConvPair(
+		func(sec int64) time.Time { return time.Unix(sec, 0).UTC() },
+		func(t time.Time) int64 { return t.Unix() },
+	)
+	defer glue.DeregConv(time.Time{}, int64(0))
+	defer glue.DeregConv(int64(0), time.Time{})
+
+	m := &Model{ID: 1, Name: "model", Created: 1024, Secret: "secret"}
+	d := &DTO{}
+	assert.NoError(t, glue.Glue(d, m, glue.DoStrict()))
+	assert.Equal(t, &DTO{Key: 1, Title: "MODEL", Created: time.Unix(1024, 0).UTC()}, d)
+
+	// the transform is not inverted.
+	back := &Model{Secret: "kept"}
+	assert.NoError(t, glue.GlueReverse(back, d, glue.DoStrict()))
+	assert.Equal(t, &Model{ID: 1, Name: "MODEL", Created: 1024, Secret: "kept"}, back)
+
+	// plain `Glue` does not see the aliases declared on DTO.
+	back = &Model{}
+	assert.Error(t, glue.Glue(back, d, glue.DoStrict()))
+
+	report, err := glue.Explain(back, d, glue.DoReverse())
+	assert.NoError(t, err)
+	assert.Contains(t, report.String(), "ID       <- Key")
+}
+
+func TestGlueReverseMapping(t *testing.T) {
+	type Foo struct {
+		A int
+		B string
+	}
+	type Bar struct {
+		X struct{ Y int }
+		C int
+	}
+	glue.NewMapping(Foo{}, Bar{}).
+		Field("A", "X.Y").
+		Field("B", "C").
+		MustRegister()
+	defer glue.DeregMapping(Foo{}, Bar{})
+
+	// B cannot be converted back without the inverse converter.
+	b := &Bar{}
+	err := glue.GlueReverse(b, &Foo{A: 2, B: "3"}, glue.DoStrict())
+	assert.ErrorIs(t, err, glue.ErrMissingConverter)
+
+	glue.MustRegConvPair(strconv.Itoa, atoiConv)
+	defer glue.DeregConv("", int(0))
+	defer glue.DeregConv(int(0), "")
+	err = glue.GlueReverse(b, &Foo{A: 2, B: "3"}, glue.DoStrict())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, b.X.Y)
+	assert.Equal(t, 3, b.C)
+
+	var foos []Foo
+	err = glue.GlueSlice(&foos, []Bar{*b})
+	assert.NoError(t, err)
+	assert.Equal(t, []Foo{{A: 2, B: "3"}}, foos)
+}
+
+func TestRegConvPair(t *testing.T) {
+	assert.Equal(t, glue.ErrNotFunction, glue.RegConvPair(1, strconv.Itoa))
+	assert.Equal(t, glue.ErrIncompatSignature, glue.RegConvPair(strconv.Itoa, strconv.Itoa))
+	assert.Equal(t, glue.ErrIncompatSignature, glue.RegConvPair(func() {}, strconv.Itoa))
+	assert.Panics(t, func() {
+		_ = glue.MustRegConvPair(strconv.Itoa, strings.ToUpper)
+	})
+}
+
+func TestGlueReverseSharedOpts(t *testing.T) {
+	type Foo struct{ A int }
+	opts := make([]glue.GlueOption, 1, 2)
+	opts[0] = glue.DoStrict()
+	assert.NoError(t, glue.GlueReverse(&Foo{}, &Foo{A: 1}, opts...))
+	// the spare capacity of the caller is left untouched.
+	assert.Nil(t, opts[:2][1])
+}
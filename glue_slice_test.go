@@ -0,0 +1,115 @@
+package glue_test
+
+import (
+	"context"
+	"errors"
+	"glue"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sModel struct {
+	ID   int
+	Name string
+}
+
+type sDTO struct {
+	ID   int
+	Name string
+}
+
+func makeModels(n int) []*sModel {
+	models := make([]*sModel, n)
+	for i := range models {
+		models[i] = &sModel{ID: i, Name: "model"}
+	}
+	return models
+}
+
+func TestGlueSlice(t *testing.T) {
+	models := makeModels(16)
+	models[3] = nil
+
+	var dtos []sDTO
+	err := glue.GlueSlice(&dtos, models)
+	assert.NoError(t, err)
+	assert.Len(t, dtos, len(models))
+	for i, d := range dtos {
+		if i == 3 {
+			assert.Equal(t, sDTO{}, d)
+			continue
+		}
+		assert.Equal(t, sDTO{ID: i, Name: "model"}, d)
+	}
+
+	var back []*sModel
+	err = glue.GlueSlice(&back, dtos)
+	assert.NoError(t, err)
+	assert.Len(t, back, len(dtos))
+	assert.Equal(t, &sModel{ID: 5, Name: "model"}, back[5])
+}
+
+func TestGlueSliceParallel(t *testing.T) {
+	models := makeModels(1024)
+
+	var dtos []*sDTO
+	err := glue.GlueSlice(&dtos, models, glue.DoParallel(8))
+	assert.NoError(t, err)
+	assert.Len(t, dtos, len(models))
+	for i, d := range dtos {
+		assert.Equal(t, i, d.ID)
+	}
+}
+
+func TestGlueSliceErrors(t *testing.T) {
+	type Foo struct {
+		ID int
+	}
+	errOdd := errors.New("odd")
+	failOdd := func(path string, dst, src reflect.Value) (reflect.Value, bool, error) {
+		if src.Int()%2 == 1 {
+			return src, false, errOdd
+		}
+		return src, true, nil
+	}
+	models := makeModels(8)
+
+	for _, n := range []int{1, 4} {
+		var foos []Foo
+		err := glue.GlueSlice(&foos, models, glue.DoFieldHook(failOdd), glue.DoParallel(n))
+		var sliceErr *glue.SliceError
+		assert.True(t, errors.As(err, &sliceErr))
+		assert.ErrorIs(t, err, errOdd)
+		assert.ErrorIs(t, err, glue.ErrGlue)
+		assert.Len(t, sliceErr.Errs, 4)
+		for i, e := range sliceErr.Errs {
+			assert.Equal(t, 2*i+1, e.Index)
+		}
+		assert.Len(t, foos, 8)
+		assert.Equal(t, 6, foos[6].ID)
+	}
+}
+
+func TestGlueSliceContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var dtos []sDTO
+	err := glue.GlueSliceContext(ctx, &dtos, makeModels(4), glue.DoParallel(2))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, dtos)
+}
+
+func TestGlueSliceInvalid(t *testing.T) {
+	var (
+		dtos []sDTO
+		ints []int
+	)
+	models := makeModels(1)
+	assert.Equal(t, glue.ErrNotSliceOfStruct, glue.GlueSlice(dtos, models))
+	assert.Equal(t, glue.ErrNotSliceOfStruct, glue.GlueSlice(&dtos, models[0]))
+	assert.Equal(t, glue.ErrNotSliceOfStruct, glue.GlueSlice(&ints, models))
+	assert.Equal(t, glue.ErrNotSliceOfStruct, glue.GlueSlice(&dtos, []int{1}))
+}
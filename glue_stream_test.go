@@ -0,0 +1,197 @@
+package glue_test
+
+import (
+	"context"
+	"errors"
+	"glue"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// feedModels sends `models` to a new channel and closes it.
+func feedModels(models []*sModel) <-chan *sModel {
+	in := make(chan *sModel)
+	go func() {
+		defer close(in)
+		for _, m := range models {
+			in <- m
+		}
+	}()
+	return in
+}
+
+// drainErrors collects the errors of a stream until closed.
+func drainErrors(errs <-chan error) <-chan []error {
+	done := make(chan []error, 1)
+	go func() {
+		var all []error
+		for err := range errs {
+			all = append(all, err)
+		}
+		done <- all
+	}()
+	return done
+}
+
+func TestStream(t *testing.T) {
+	models := makeModels(64)
+	models[3] = nil
+
+	for _, opts := range [][]glue.GlueOption{
+		nil,
+		{glue.DoParallel(4)},
+	} {
+		out := make(chan sDTO)
+		errs := drainErrors(glue.Stream(context.Background(), out, feedModels(models), opts...))
+
+		var ids []int
+		for d := range out {
+			ids = append(ids, d.ID)
+			assert.Equal(t, "model", d.Name)
+		}
+		assert.Empty(t, <-errs)
+		assert.Len(t, ids, len(models)-1)
+		assert.True(t, sort.IntsAreSorted(ids))
+		assert.NotContains(t, ids, 3)
+	}
+}
+
+func TestStreamUnordered(t *testing.T) {
+	models := makeModels(256)
+	out := make(chan *sDTO)
+	errs := drainErrors(glue.Stream(
+		context.Background(), out, feedModels(models),
+		glue.DoParallel(8), glue.DoUnordered(),
+	))
+
+	var ids []int
+	for d := range out {
+		ids = append(ids, d.ID)
+	}
+	assert.Empty(t, <-errs)
+	sort.Ints(ids)
+	for i, id := range ids {
+		assert.Equal(t, i, id)
+	}
+	assert.Len(t, ids, len(models))
+}
+
+func TestStreamIterator(t *testing.T) {
+	i := 0
+	next := func() (sModel, bool) {
+		if i == 5 {
+			return sModel{}, false
+		}
+		i++
+		return sModel{ID: i}, true
+	}
+	out := make(chan sDTO, 5)
+	errs := glue.Stream(context.Background(), out, next)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	var ids []int
+	for d := range out {
+		ids = append(ids, d.ID)
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, ids)
+}
+
+func TestStreamErrors(t *testing.T) {
+	type Foo struct {
+		ID int
+	}
+	errOdd := errors.New("odd")
+	failOdd := func(path string, dst, src reflect.Value) (reflect.Value, bool, error) {
+		if src.Int()%2 == 1 {
+			return src, false, errOdd
+		}
+		return src, true, nil
+	}
+
+	for _, n := range []int{1, 4} {
+		out := make(chan Foo)
+		errs := drainErrors(glue.Stream(
+			context.Background(), out, feedModels(makeModels(8)),
+			glue.DoFieldHook(failOdd), glue.DoParallel(n),
+		))
+		var ids []int
+		for f := range out {
+			ids = append(ids, f.ID)
+		}
+		assert.Equal(t, []int{0, 2, 4, 6}, ids)
+
+		all := <-errs
+		assert.Len(t, all, 4)
+		for i, err := range all {
+			var ie *glue.IndexError
+			assert.True(t, errors.As(err, &ie))
+			assert.Equal(t, 2*i+1, ie.Index)
+			assert.ErrorIs(t, err, errOdd)
+		}
+	}
+
+	// invalid arguments are reported at once.
+	errs := glue.Stream(context.Background(), make(chan int), feedModels(nil))
+	assert.Equal(t, glue.ErrNotChanOfStruct, <-errs)
+	_, ok := <-errs
+	assert.False(t, ok)
+}
+
+func TestStreamSetupError(t *testing.T) {
+	type Foo struct {
+		ID      int
+		Missing int
+	}
+	out := make(chan *Foo)
+	errs := drainErrors(glue.Stream(
+		context.Background(), out, feedModels(makeModels(2)), glue.DoStrict(),
+	))
+	// `out` is closed even though the stream does not start.
+	n := 0
+	for range out {
+		n++
+	}
+	assert.Equal(t, 0, n)
+	all := <-errs
+	assert.Len(t, all, 1)
+	assert.ErrorIs(t, all[0], glue.ErrUnsatisfiedField)
+}
+
+func TestStreamCancel(t *testing.T) {
+	for _, opts := range [][]glue.GlueOption{
+		nil,
+		{glue.DoParallel(4)},
+		{glue.DoParallel(4), glue.DoUnordered()},
+	} {
+		ctx, cancel := context.WithCancel(context.Background())
+		in := make(chan sModel)
+		go func() {
+			// endless input, only the cancellation ends the stream.
+			for i := 0; ; i++ {
+				select {
+				case in <- sModel{ID: i}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		out := make(chan sDTO)
+		errs := drainErrors(glue.Stream(ctx, out, in, opts...))
+
+		n := 0
+		for range out {
+			n++
+			if n == 10 {
+				cancel()
+			}
+		}
+		cancel()
+		all := <-errs
+		assert.NotEmpty(t, all)
+		assert.ErrorIs(t, all[len(all)-1], context.Canceled)
+	}
+}
@@ -0,0 +1,144 @@
+package glue_test
+
+import (
+	"glue"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagKeysJSON(t *testing.T) {
+	type Model struct {
+		UserID string `json:"user_id"`
+		Name   string `json:"name,omitempty"`
+		Secret string `json:"-"`
+	}
+	type DTO struct {
+		UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3"`
+		Name   string `protobuf:"bytes,2,opt,name=name,proto3"`
+		Secret string `protobuf:"bytes,3,opt,name=secret,proto3"`
+	}
+	m := &Model{Secret: "untouched"}
+	d := &DTO{UserId: "u-1024", Name: "glue", Secret: "pwd"}
+
+	opts := []glue.GlueOption{
+		glue.DoTagKeys("json", "protobuf"),
+	}
+	err := glue.Glue(m, d, opts...)
+	assert.NoError(t, err)
+	assert.Equal(t, "u-1024", m.UserID)
+	assert.Equal(t, "glue", m.Name)
+	assert.Equal(t, "untouched", m.Secret)
+}
+
+func TestTagKeysFallbackOrder(t *testing.T) {
+	type Foo struct {
+		A int `glue:"B" db:"c"`
+		D int `db:"e,omitempty"`
+		F int `json:",omitempty" db:"G"`
+	}
+	type Bar struct {
+		B int
+		C int `db:"c"`
+		E int `db:"e"`
+		G int
+	}
+	f := &Foo{}
+	b := &Bar{B: 1, C: 2, E: 3, G: 4}
+
+	err := glue.Glue(f, b, glue.DoTagKeys("glue", "db"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, f.A)
+	assert.Equal(t, 3, f.D)
+	assert.Equal(t, 4, f.F)
+
+	f = &Foo{}
+	err = glue.Glue(f, b, glue.DoTagKeys("db", "glue"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, f.A)
+
+	f = &Foo{}
+	err = glue.Glue(f, b, glue.DoTagKeys("json"))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, f.F)
+}
+
+func TestTagKeysIgnoreGlueTag(t *testing.T) {
+	type Foo struct {
+		A int `glue:"B"`
+	}
+	type Bar struct {
+		A int
+		B int
+	}
+	f := &Foo{}
+	b := &Bar{A: 1, B: 2}
+
+	err := glue.Glue(f, b, glue.DoTagKeys("json"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, f.A)
+
+	err = glue.Glue(f, b)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, f.A)
+}
+
+func TestTagKeysFavorSource(t *testing.T) {
+	type Foo struct {
+		UserID string
+	}
+	type Bar struct {
+		ID string `json:"UserID"`
+	}
+	f := &Foo{}
+	b := &Bar{ID: "u-1"}
+
+	err := glue.Glue(f, b, glue.DoTagKeys("json"), glue.DoFavorSource())
+	assert.NoError(t, err)
+	assert.Equal(t, "u-1", f.UserID)
+}
+
+func TestGroups(t *testing.T) {
+	type (
+		User struct {
+			Name  string
+			Email string
+			Notes string
+		}
+		UserView struct {
+			Name  string
+			Email string `glue:",groups=admin|owner"`
+			Notes string `glue:"Notes,groups=admin"`
+		}
+	)
+	u := &User{Name: "name", Email: "mail", Notes: "notes"}
+
+	v := &UserView{}
+	assert.NoError(t, glue.Glue(v, u, glue.DoStrict()))
+	assert.Equal(t, &UserView{Name: "name"}, v)
+
+	v = &UserView{}
+	assert.NoError(t, glue.Glue(v, u, glue.DoGroups("owner")))
+	assert.Equal(t, &UserView{Name: "name", Email: "mail"}, v)
+
+	v = &UserView{}
+	assert.NoError(t, glue.Glue(v, u, glue.DoGroups("guest"), glue.DoGroups("admin")))
+	assert.Equal(t, &UserView{Name: "name", Email: "mail", Notes: "notes"}, v)
+
+	// the groups are read in push mode from the source.
+	back := &User{}
+	assert.NoError(t, glue.Glue(back, v, glue.DoFavorSource(), glue.DoGroups("owner")))
+	assert.Equal(t, &User{Name: "name", Email: "mail"}, back)
+}
+
+func TestGroupsInvalid(t *testing.T) {
+	type (
+		Src  struct{ A int }
+		Dest struct {
+			A int `glue:",groups=admin||owner"`
+		}
+	)
+	assert.Panics(t, func() {
+		_ = glue.Glue(&Dest{}, &Src{}, glue.DoGroups("admin"))
+	})
+}
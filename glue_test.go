@@ -349,3 +349,184 @@ func TestPullEmbedded(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, b.Emb.A, f.A)
 }
+
+func TestGlueNilEmbedded(t *testing.T) {
+	type (
+		EFoo struct {
+			A int
+		}
+		eBar struct {
+			A int
+			B int
+		}
+		eBaz struct {
+			B int
+			*EFoo
+		}
+	)
+	eb := &eBar{A: -1}
+	ez := &eBaz{B: 1000}
+
+	err := glue.Glue(eb, ez)
+	assert.NoError(t, err)
+	assert.Equal(t, -1, eb.A)
+	assert.Equal(t, 1000, eb.B)
+
+	err = glue.Glue(eb, ez, glue.DoStrict())
+	assert.ErrorIs(t, err, glue.ErrUnsatisfiedField)
+	assert.Equal(t, -1, eb.A)
+
+	err = glue.Glue(eb, ez, glue.DoNilAsZero())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, eb.A)
+
+	ez.EFoo = &EFoo{A: 9977}
+	err = glue.Glue(eb, ez, glue.DoStrict())
+	assert.NoError(t, err)
+	assert.Equal(t, 9977, eb.A)
+}
+
+func TestGlueAllocEmbedded(t *testing.T) {
+	type (
+		EFoo struct {
+			A int
+		}
+		eBar struct {
+			B int
+			*EFoo
+		}
+		eBaz struct {
+			A int
+			B int
+		}
+		eQux struct {
+			B int
+		}
+	)
+	var ans_a int = 9977
+
+	eb := &eBar{}
+	err := glue.Glue(eb, &eQux{B: 1}, glue.DoFavorSource())
+	assert.NoError(t, err)
+	assert.Nil(t, eb.EFoo)
+
+	err = glue.Glue(eb, &eBaz{A: ans_a, B: 1}, glue.DoFavorSource())
+	assert.NoError(t, err)
+	assert.NotNil(t, eb.EFoo)
+	assert.Equal(t, ans_a, eb.EFoo.A)
+	assert.Equal(t, 1, eb.B)
+}
+
+type inner struct {
+	ID int
+}
+
+func TestPullUnexportedEmbedded(t *testing.T) {
+	type (
+		Foo struct {
+			ID int
+		}
+		Bar struct {
+			inner
+			X int
+		}
+		Baz struct {
+			*inner
+		}
+	)
+	f := &Foo{ID: -1}
+	err := glue.Glue(f, &Bar{inner: inner{ID: 1023}}, glue.DoStrict())
+	assert.NoError(t, err)
+	assert.Equal(t, 1023, f.ID)
+
+	err = glue.Glue(f, &Baz{inner: &inner{ID: 511}}, glue.DoStrict())
+	assert.NoError(t, err)
+	assert.Equal(t, 511, f.ID)
+
+	err = glue.Glue(f, &Baz{})
+	assert.NoError(t, err)
+	assert.Equal(t, 511, f.ID)
+}
+
+func TestStrictNilUntouched(t *testing.T) {
+	type (
+		Foo struct {
+			A  int
+			ID int
+		}
+		Baz struct {
+			A int
+			*inner
+		}
+	)
+	// the nil pointer is found before any field is copied.
+	f := &Foo{A: 1, ID: 2}
+	err := glue.Glue(f, &Baz{A: 99}, glue.DoStrict())
+	assert.ErrorIs(t, err, glue.ErrUnsatisfiedField)
+	assert.Contains(t, err.Error(), "behind nil pointer")
+	assert.Equal(t, &Foo{A: 1, ID: 2}, f)
+
+	b := &Baz{}
+	err = glue.Glue(b, &Foo{A: 99, ID: 511}, glue.DoStrict())
+	assert.ErrorIs(t, err, glue.ErrUnsatisfiedField)
+	assert.Equal(t, &Baz{}, b)
+}
+
+func TestPushUnexportedEmbedded(t *testing.T) {
+	type (
+		Foo struct {
+			ID int
+		}
+		Bar struct {
+			inner
+			X int
+		}
+		Baz struct {
+			*inner
+		}
+	)
+	b := &Bar{}
+	err := glue.Glue(b, &Foo{ID: 1023})
+	assert.NoError(t, err)
+	assert.Equal(t, 1023, b.ID)
+
+	b = &Bar{}
+	err = glue.Glue(b, &Foo{ID: 1023}, glue.DoFavorSource())
+	assert.NoError(t, err)
+	assert.Equal(t, 1023, b.ID)
+
+	z := &Baz{inner: &inner{}}
+	err = glue.Glue(z, &Foo{ID: 511})
+	assert.NoError(t, err)
+	assert.Equal(t, 511, z.ID)
+
+	// a nil pointer to unexported struct cannot be allocated by reflection.
+	z = &Baz{}
+	err = glue.Glue(z, &Foo{ID: 511})
+	assert.NoError(t, err)
+	assert.Nil(t, z.inner)
+	err = glue.Glue(z, &Foo{ID: 511}, glue.DoStrict())
+	assert.ErrorIs(t, err, glue.ErrUnsatisfiedField)
+}
+
+func TestGlueNoAlloc(t *testing.T) {
+	type Foo struct {
+		A int
+		B string
+		C []byte
+		D float64
+	}
+	type Bar struct {
+		Foo
+		E bool
+	}
+	a := &Foo{}
+	b := &Bar{Foo: Foo{A: 1, B: "b", C: []byte("c"), D: 4}}
+	assert.NoError(t, glue.Glue(a, b))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = glue.Glue(a, b)
+	})
+	assert.Zero(t, allocs)
+	assert.Equal(t, b.Foo, *a)
+}
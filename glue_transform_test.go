@@ -0,0 +1,114 @@
+package glue_test
+
+import (
+	"errors"
+	"glue"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformTag(t *testing.T) {
+	type Foo struct {
+		Email string `glue:",transform=lower"`
+		Name  string `glue:"Nick,transform=trim"`
+		Memo  string
+	}
+	type Bar struct {
+		Email string
+		Nick  string
+		Memo  string
+	}
+	glue.MustRegTransform("lower", strings.ToLower)
+	glue.MustRegTransform("trim", strings.TrimSpace)
+	defer glue.DeregTransform("lower")
+	defer glue.DeregTransform("trim")
+
+	f := &Foo{}
+	b := &Bar{Email: "Glue@Example.COM", Nick: "  glue ", Memo: " Memo "}
+	err := glue.Glue(f, b)
+	assert.NoError(t, err)
+	assert.Equal(t, "glue@example.com", f.Email)
+	assert.Equal(t, "glue", f.Name)
+	assert.Equal(t, " Memo ", f.Memo)
+}
+
+func TestTransformInvalid(t *testing.T) {
+	type Foo struct {
+		A string `glue:",transform=missing"`
+	}
+	type Baz struct {
+		A string `glue:",transform=neg"`
+	}
+	type Qux struct {
+		A string `glue:",unknown"`
+	}
+	type Bar struct {
+		A string
+	}
+	var err error
+
+	err = glue.RegTransform("bad", strings.Repeat)
+	assert.ErrorIs(t, err, glue.ErrIncompatSignature)
+	err = glue.RegTransform("bad", "lower")
+	assert.ErrorIs(t, err, glue.ErrNotFunction)
+
+	glue.MustRegTransform("neg", func(n int) int { return -n })
+	defer glue.DeregTransform("neg")
+
+	err = glue.Glue(&Foo{}, &Bar{})
+	assert.ErrorIs(t, err, glue.ErrUnknownTransform)
+	err = glue.Glue(&Baz{}, &Bar{})
+	assert.ErrorIs(t, err, glue.ErrIncompatSignature)
+	assert.ErrorIs(t, glue.Check(&Baz{}, &Bar{}), glue.ErrIncompatSignature)
+	assert.Panics(t, func() {
+		_ = glue.Glue(&Qux{}, &Bar{})
+	})
+	assert.ErrorIs(t, glue.Check(&Qux{}, &Bar{}), glue.ErrInvalidTag)
+}
+
+func TestFieldHook(t *testing.T) {
+	type Foo struct {
+		Name     string
+		Password string
+		Age      int
+	}
+	type Bar struct {
+		Name     string
+		Password string
+		Age      int
+	}
+	var paths []string
+	redact := func(path string, dst, src reflect.Value) (reflect.Value, bool, error) {
+		paths = append(paths, path)
+		if path == "Password" {
+			return reflect.ValueOf("***"), true, nil
+		}
+		return src, true, nil
+	}
+	keepAge := func(path string, dst, src reflect.Value) (reflect.Value, bool, error) {
+		return src, path != "Age", nil
+	}
+	f := &Foo{Age: 18}
+	b := &Bar{Name: "glue", Password: "secret", Age: 99}
+
+	err := glue.Glue(f, b, glue.DoFieldHook(redact), glue.DoFieldHook(keepAge))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Name", "Password", "Age"}, paths)
+	assert.Equal(t, "glue", f.Name)
+	assert.Equal(t, "***", f.Password)
+	assert.Equal(t, 18, f.Age)
+
+	errReject := errors.New("reject")
+	reject := func(path string, dst, src reflect.Value) (reflect.Value, bool, error) {
+		return src, false, errReject
+	}
+	var fieldErr *glue.FieldError
+	err = glue.Glue(f, b, glue.DoFieldHook(reject))
+	assert.ErrorIs(t, err, errReject)
+	assert.ErrorIs(t, err, glue.ErrGlue)
+	assert.True(t, errors.As(err, &fieldErr))
+	assert.Equal(t, "Name", fieldErr.Path)
+}
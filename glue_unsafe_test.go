@@ -0,0 +1,193 @@
+package glue_test
+
+import (
+	"glue"
+	"math/rand"
+	"reflect"
+	"runtime"
+	"strconv"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnsafeCopy(t *testing.T) {
+	type (
+		Meta struct {
+			Tags []string
+			Rev  int32
+		}
+		Model struct {
+			ID      int64
+			Name    string
+			Score   float64
+			Owner   *int
+			Flags   [3]bool
+			Created int64
+			Meta
+			secret string
+			Note   string
+		}
+		DTO struct {
+			ID      int64
+			Name    string
+			Score   float64
+			Owner   *int
+			Flags   [3]bool
+			Created string // breaks the run.
+			Tags    []string
+			Rev     int32
+			Note    string
+		}
+	)
+	owner := 7
+	m := &Model{
+		ID: 1, Name: "model", Score: 0.5, Owner: &owner,
+		Flags: [3]bool{true, false, true}, Created: 1024,
+		Meta:   Meta{Tags: []string{"a", "b"}, Rev: 3},
+		secret: "secret", Note: "note",
+	}
+
+	var plain, fast DTO
+	assert.NoError(t, glue.Glue(&plain, m))
+	assert.NoError(t, glue.Glue(&fast, m, glue.DoUnsafeCopy()))
+	assert.Equal(t, plain, fast)
+	assert.Equal(t, &owner, fast.Owner)
+	assert.Equal(t, m.Tags, fast.Tags)
+	assert.Equal(t, "", fast.Created)
+
+	// the other way round.
+	back := &Model{}
+	assert.NoError(t, glue.Glue(back, &fast, glue.DoUnsafeCopy(), glue.DoFavorSource()))
+	assert.Equal(t, "", back.secret)
+	back.secret, back.Created = m.secret, m.Created
+	assert.Equal(t, m, back)
+}
+
+// runTypes are the types of the fields of random structs.
+var runTypes = []reflect.Type{
+	reflect.TypeOf(int8(0)),
+	reflect.TypeOf(int16(0)),
+	reflect.TypeOf(int32(0)),
+	reflect.TypeOf(int64(0)),
+	reflect.TypeOf(uint8(0)),
+	reflect.TypeOf(float32(0)),
+	reflect.TypeOf(float64(0)),
+	reflect.TypeOf(complex128(0)),
+	reflect.TypeOf(false),
+	reflect.TypeOf(""),
+	reflect.TypeOf([]int{}),
+	reflect.TypeOf((*int)(nil)),
+	reflect.TypeOf(map[string]int{}),
+	reflect.TypeOf([3]byte{}),
+	reflect.TypeOf([2]string{}),
+	reflect.TypeOf(struct {
+		A int8
+		B *int
+	}{}),
+}
+
+// randomPair makes a random src struct type and a dst type that shares most
+// of its fields, with some fields dropped, retyped, added or swapped.
+func randomPair(rnd *rand.Rand) (dst, src reflect.Type) {
+	var (
+		n         = 1 + rnd.Intn(16)
+		srcFields = make([]reflect.StructField, n)
+		dstFields []reflect.StructField
+	)
+	for i := range srcFields {
+		srcFields[i] = reflect.StructField{
+			Name: "F" + strconv.Itoa(i),
+			Type: runTypes[rnd.Intn(len(runTypes))],
+		}
+	}
+	for i, sf := range srcFields {
+		switch rnd.Intn(10) {
+		case 0:
+			// dropped.
+		case 1:
+			sf.Type = runTypes[rnd.Intn(len(runTypes))]
+			dstFields = append(dstFields, sf)
+		case 2:
+			dstFields = append(dstFields, reflect.StructField{
+				Name: "X" + strconv.Itoa(i),
+				Type: runTypes[rnd.Intn(len(runTypes))],
+			}, sf)
+		default:
+			dstFields = append(dstFields, sf)
+		}
+	}
+	if len(dstFields) > 1 && rnd.Intn(4) == 0 {
+		i, j := rnd.Intn(len(dstFields)), rnd.Intn(len(dstFields))
+		dstFields[i], dstFields[j] = dstFields[j], dstFields[i]
+	}
+	return reflect.StructOf(dstFields), reflect.StructOf(srcFields)
+}
+
+func TestUnsafeCopyRandom(t *testing.T) {
+	sameAsPlain := func(seed int64) bool {
+		var (
+			rnd           = rand.New(rand.NewSource(seed))
+			dstT, srcT    = randomPair(rnd)
+			src, _        = quick.Value(srcT, rnd)
+			init, _       = quick.Value(dstT, rnd)
+			plain         = reflect.New(dstT)
+			fast          = reflect.New(dstT)
+			pSrc          = reflect.New(srcT)
+			favor, favorP = reflect.New(srcT), reflect.New(srcT)
+		)
+		pSrc.Elem().Set(src)
+		plain.Elem().Set(init)
+		fast.Elem().Set(init)
+		favor.Elem().Set(src)
+		favorP.Elem().Set(src)
+
+		err := glue.Glue(plain.Interface(), pSrc.Interface())
+		if err != nil {
+			return false
+		}
+		err = glue.Glue(fast.Interface(), pSrc.Interface(), glue.DoUnsafeCopy())
+		if err != nil {
+			return false
+		}
+		// push back into a copy of src.
+		err = glue.Glue(favorP.Interface(), fast.Interface(), glue.DoFavorSource())
+		if err != nil {
+			return false
+		}
+		err = glue.Glue(
+			favor.Interface(), fast.Interface(),
+			glue.DoFavorSource(), glue.DoUnsafeCopy(),
+		)
+		if err != nil {
+			return false
+		}
+		if rnd.Intn(8) == 0 {
+			// the copied pointers must be known to the GC.
+			runtime.GC()
+		}
+		return reflect.DeepEqual(plain.Interface(), fast.Interface()) &&
+			reflect.DeepEqual(favorP.Interface(), favor.Interface())
+	}
+	err := quick.Check(sameAsPlain, &quick.Config{MaxCount: 2000})
+	assert.NoError(t, err)
+}
+
+func TestUnsafeCopyHook(t *testing.T) {
+	type Foo struct {
+		A int
+		B int
+		C int
+	}
+	var paths []string
+	hook := func(path string, dst, src reflect.Value) (reflect.Value, bool, error) {
+		paths = append(paths, path)
+		return src, true, nil
+	}
+	f := &Foo{}
+	err := glue.Glue(f, &Foo{1, 2, 3}, glue.DoUnsafeCopy(), glue.DoFieldHook(hook))
+	assert.NoError(t, err)
+	assert.Equal(t, &Foo{1, 2, 3}, f)
+	assert.Equal(t, []string{"A", "B", "C"}, paths)
+}
@@ -0,0 +1,59 @@
+// Package gluetest provides test helpers asserting that a structure mirrors
+// another through the field matching of package `glue`, in the style of
+// testify.
+package gluetest
+
+import (
+	"fmt"
+	"glue"
+	"reflect"
+	"strings"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tHelper interface {
+	Helper()
+}
+
+// AssertEqual asserts that every field of `dst` matched in `src` holds the
+// value `glue.Glue(dst, src, opts...)` would write, see `glue.Equal`. All the
+// mismatching fields are reported on failure.
+//
+//	gluetest.AssertEqual(t, &UserDTO{}, &User{})
+func AssertEqual(t assert.TestingT, dst, src interface{}, opts ...glue.GlueOption) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	changes, err := glue.Diff(dst, src, opts...)
+	if err != nil {
+		return assert.Fail(t, fmt.Sprintf("Cannot compare by glue: %v", err))
+	}
+	if len(changes) == 0 {
+		return true
+	}
+	return assert.Fail(t, mismatchMessage(dst, src, changes))
+}
+
+// RequireEqual is `AssertEqual` that stops the test on failure.
+func RequireEqual(t require.TestingT, dst, src interface{}, opts ...glue.GlueOption) {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	if !AssertEqual(t, dst, src, opts...) {
+		t.FailNow()
+	}
+}
+
+func mismatchMessage(dst, src interface{}, changes []glue.Change) string {
+	var b strings.Builder
+	fmt.Fprintf(
+		&b, "%v does not mirror %v, %d field(s) mismatch:",
+		reflect.TypeOf(dst), reflect.TypeOf(src), len(changes),
+	)
+	for _, c := range changes {
+		fmt.Fprintf(&b, "\n\t%s: %#v, expected %#v", c.Path, c.Old, c.New)
+	}
+	return b.String()
+}
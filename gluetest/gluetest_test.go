@@ -0,0 +1,143 @@
+package gluetest_test
+
+import (
+	"fmt"
+	"glue"
+	"glue/gluetest"
+	"math/rand"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder records the failures of helpers.
+type recorder struct {
+	msgs   []string
+	failed bool
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.msgs = append(r.msgs, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) FailNow() {
+	r.failed = true
+}
+
+type model struct {
+	ID   int
+	Name string
+	Tags []string
+}
+
+type dto struct {
+	ID    int
+	Name  string `glue:"Name"`
+	Tags  []string
+	Extra bool
+}
+
+func TestAssertEqual(t *testing.T) {
+	m := &model{ID: 1, Name: "model", Tags: []string{"a"}}
+	gluetest.AssertEqual(t, &dto{ID: 1, Name: "model", Tags: []string{"a"}}, m)
+
+	r := &recorder{}
+	ok := gluetest.AssertEqual(r, &dto{ID: 2, Name: "model"}, m)
+	assert.False(t, ok)
+	assert.Len(t, r.msgs, 1)
+	assert.Contains(t, r.msgs[0], "2 field(s) mismatch")
+	assert.Contains(t, r.msgs[0], "ID: 2, expected 1")
+	assert.Contains(t, r.msgs[0], `Tags: []string(nil), expected []string{"a"}`)
+
+	r = &recorder{}
+	gluetest.AssertEqual(r, dto{}, m)
+	assert.Contains(t, r.msgs[0], "Cannot compare by glue")
+}
+
+func TestRequireEqual(t *testing.T) {
+	m := &model{ID: 1}
+	r := &recorder{}
+	gluetest.RequireEqual(r, &dto{ID: 1}, m)
+	assert.False(t, r.failed)
+
+	gluetest.RequireEqual(r, &dto{}, m)
+	assert.True(t, r.failed)
+}
+
+type event struct {
+	ID    int
+	At    time.Time
+	Score float64
+	Meta  struct {
+		Source string
+	}
+	note string
+}
+
+type eventDTO struct {
+	Key   int `glue:"ID"`
+	At    int64
+	Score float32
+	Meta  struct {
+		Source string
+	}
+}
+
+func registerLossy() func() {
+	glue.MustRegConvPair(
+		func(t time.Time) int64 { return t.Unix() },
+		func(sec int64) time.Time { return time.Unix(sec, 0) },
+	)
+	glue.MustRegConvPair(
+		func(f float64) float32 { return float32(f) },
+		func(f float32) float64 { return float64(f) },
+	)
+	return func() {
+		glue.DeregConv(int64(0), time.Time{})
+		glue.DeregConv(time.Time{}, int64(0))
+		glue.DeregConv(float32(0), float64(0))
+		glue.DeregConv(float64(0), float32(0))
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	defer registerLossy()()
+
+	sample := &event{ID: 1, At: time.Unix(1024, 0).UTC(), Score: 0.5, note: "not glued"}
+	sample.Meta.Source = "source"
+	back := &event{}
+	gluetest.RoundTrip(t, sample, back, &eventDTO{})
+	assert.Equal(t, 1, back.ID)
+
+	r := &recorder{}
+	sample.At = time.Unix(1024, 512)
+	sample.Score = 0.1
+	ok := gluetest.RoundTrip(r, sample, &event{}, &eventDTO{})
+	assert.False(t, ok)
+	assert.Contains(t, r.msgs[0], "2 field(s) lost")
+	assert.Contains(t, r.msgs[0], "\tAt: sent")
+	assert.Contains(t, r.msgs[0], "\tScore: sent 0.1, got back 0.10000000149011612")
+
+	r = &recorder{}
+	gluetest.RoundTrip(r, sample, &event{}, eventDTO{})
+	assert.Contains(t, r.msgs[0], "Cannot round trip by glue")
+}
+
+func TestRoundTripQuick(t *testing.T) {
+	type lossless struct {
+		Key   int `glue:"ID"`
+		At    time.Time
+		Score float64
+	}
+	config := &quick.Config{MaxCount: 50, Rand: rand.New(rand.NewSource(1))}
+	gluetest.RoundTripQuick(t, &event{}, &lossless{}, config)
+
+	defer registerLossy()()
+	r := &recorder{}
+	ok := gluetest.RoundTripQuick(r, &event{}, &eventDTO{}, config)
+	assert.False(t, ok)
+	assert.Contains(t, r.msgs[0], "sample #0")
+	assert.Contains(t, r.msgs[0], "\tAt: sent")
+}
@@ -0,0 +1,217 @@
+package gluetest
+
+import (
+	"fmt"
+	"glue"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing/quick"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RoundTrip asserts that the fields of `sample` survive a round trip, that is
+// gluing `sample` into `dst` and gluing `dst` back into `back` by
+// `glue.GlueReverse`, with the registered converters on the way. Every field
+// of `sample` glued into `dst` is compared with its counterpart in `back`,
+// by the `Equal` method if the type has one (like `time.Time`) or
+// `reflect.DeepEqual` otherwise, all the fields that do not survive are
+// reported on failure.
+//
+//	gluetest.RoundTrip(t, &Model{Created: time.Now()}, &Model{}, &DTO{})
+func RoundTrip(t assert.TestingT, sample, back, dst interface{}, opts ...glue.GlueOption) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	lost, err := roundTrip(sample, back, dst, opts)
+	if err != nil {
+		return assert.Fail(t, fmt.Sprintf("Cannot round trip by glue: %v", err))
+	}
+	if len(lost) == 0 {
+		return true
+	}
+	return assert.Fail(t, lostMessage(sample, dst, lost))
+}
+
+// RoundTripQuick is `RoundTrip` on random samples of the type `back` points
+// to, it reports the first sample that does not survive. The exported fields
+// of the samples are generated by `testing/quick`, as well as `time.Time` in
+// nanoseconds, fields that cannot be generated are left zero. `config` sets
+// the number of samples and the source of randomness, nil means the defaults
+// of `testing/quick`.
+func RoundTripQuick(
+	t assert.TestingT, back, dst interface{}, config *quick.Config,
+	opts ...glue.GlueOption,
+) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	var (
+		backType = reflect.TypeOf(back)
+		dstType  = reflect.TypeOf(dst)
+		count    = 100
+		rnd      *rand.Rand
+	)
+	if backType.Kind() != reflect.Ptr || dstType.Kind() != reflect.Ptr {
+		return assert.Fail(
+			t, fmt.Sprintf("Cannot round trip by glue: %v", glue.ErrNotPtrToStruct),
+		)
+	}
+	if config != nil && config.MaxCount > 0 {
+		count = config.MaxCount
+	}
+	if config != nil && config.Rand != nil {
+		rnd = config.Rand
+	} else {
+		rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	for i := 0; i < count; i++ {
+		sample := reflect.New(backType.Elem())
+		randomStruct(sample.Elem(), rnd, 0)
+		lost, err := roundTrip(
+			sample.Interface(), reflect.New(backType.Elem()).Interface(),
+			reflect.New(dstType.Elem()).Interface(), opts,
+		)
+		if err != nil {
+			return assert.Fail(t, fmt.Sprintf("Cannot round trip by glue: %v", err))
+		}
+		if len(lost) > 0 {
+			return assert.Fail(t, fmt.Sprintf(
+				"%s\nsample #%d: %#v", lostMessage(back, dst, lost), i, sample.Elem(),
+			))
+		}
+	}
+	return true
+}
+
+// lostField is a field that does not survive a round trip.
+type lostField struct {
+	Path      string
+	Sent, Got interface{}
+}
+
+// roundTrip glues `sample` there and back, it returns the fields that do not
+// survive.
+func roundTrip(sample, back, dst interface{}, opts []glue.GlueOption) ([]lostField, error) {
+	if err := glue.Glue(dst, sample, opts...); err != nil {
+		return nil, err
+	}
+	if err := glue.GlueReverse(back, dst, opts...); err != nil {
+		return nil, err
+	}
+	report, err := glue.Explain(dst, sample, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		lost     []lostField
+		sent     = reflect.ValueOf(sample).Elem()
+		returned = reflect.ValueOf(back).Elem()
+	)
+	for _, fr := range report.Fields {
+		if !fr.Copied {
+			continue
+		}
+		want, ok := fieldByPath(sent, fr.Src)
+		if !ok {
+			// not glued from behind a nil pointer.
+			continue
+		}
+		got, ok := fieldByPath(returned, fr.Src)
+		if ok && valuesEqual(want, got) {
+			continue
+		}
+		lf := lostField{Path: fr.Src, Sent: want.Interface()}
+		if ok {
+			lf.Got = got.Interface()
+		}
+		lost = append(lost, lf)
+	}
+	return lost, nil
+}
+
+func lostMessage(sample, dst interface{}, lost []lostField) string {
+	var b strings.Builder
+	fmt.Fprintf(
+		&b, "%v does not survive the round trip through %v, %d field(s) lost:",
+		reflect.TypeOf(sample), reflect.TypeOf(dst), len(lost),
+	)
+	for _, lf := range lost {
+		fmt.Fprintf(&b, "\n\t%s: sent %#v, got back %#v", lf.Path, lf.Sent, lf.Got)
+	}
+	return b.String()
+}
+
+// fieldByPath returns the field at the dotted `path` of `v`, it reports false
+// if there is a nil pointer on the way.
+func fieldByPath(v reflect.Value, path string) (reflect.Value, bool) {
+	for _, name := range strings.Split(path, ".") {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		}
+		v = v.FieldByName(name)
+	}
+	return v, v.IsValid() && v.CanInterface()
+}
+
+// valuesEqual compares by the `Equal` method of `want` if it has one.
+func valuesEqual(want, got reflect.Value) bool {
+	if eq := want.MethodByName("Equal"); eq.IsValid() {
+		et := eq.Type()
+		if et.NumIn() == 1 && et.In(0) == got.Type() &&
+			et.NumOut() == 1 && et.Out(0).Kind() == reflect.Bool {
+			return eq.Call([]reflect.Value{got})[0].Bool()
+		}
+	}
+	return reflect.DeepEqual(want.Interface(), got.Interface())
+}
+
+var typeTime = reflect.TypeOf(time.Time{})
+
+// maxDepth limits the nested structs a sample is generated into.
+const maxDepth = 4
+
+// randomStruct fills the exported fields of the struct `v` randomly.
+func randomStruct(v reflect.Value, rnd *rand.Rand, depth int) {
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if !f.CanSet() {
+			continue
+		}
+		switch {
+		case f.Type() == typeTime:
+			sec, nsec := rnd.Int63n(1<<34), rnd.Int63n(int64(time.Second))
+			f.Set(reflect.ValueOf(time.Unix(sec, nsec).UTC()))
+		case f.Kind() == reflect.Struct && depth < maxDepth:
+			randomStruct(f, rnd, depth+1)
+		case f.Kind() == reflect.Ptr && f.Type().Elem().Kind() == reflect.Struct:
+			if depth < maxDepth {
+				p := reflect.New(f.Type().Elem())
+				randomStruct(p.Elem(), rnd, depth+1)
+				f.Set(p)
+			}
+		default:
+			if rv, ok := quickValue(f.Type(), rnd); ok {
+				f.Set(rv)
+			}
+		}
+	}
+}
+
+// quickValue is `quick.Value` that reports false instead of panicking on
+// types it cannot generate, like structs with unexported fields.
+func quickValue(t reflect.Type, rnd *rand.Rand) (v reflect.Value, ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	return quick.Value(t, rnd)
+}
@@ -0,0 +1,81 @@
+package glue
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// BeforeGluer is implemented by the destination that needs to act before the
+// fields of src are glued into it.
+type BeforeGluer interface {
+	BeforeGlue(src interface{}) error
+}
+
+// AfterGluer is implemented by the destination that needs to normalize or
+// validate itself after the fields are glued.
+type AfterGluer interface {
+	AfterGlue(src interface{}) error
+}
+
+// BeforeGlueOuter is implemented by the source that needs to act before its
+// fields are pushed to dst, it is only called under `DoFavorSource`.
+type BeforeGlueOuter interface {
+	BeforeGlueOut(dst interface{}) error
+}
+
+// AfterGlueOuter is implemented by the source that needs to act after its
+// fields are pushed to dst, it is only called under `DoFavorSource`.
+type AfterGlueOuter interface {
+	AfterGlueOut(dst interface{}) error
+}
+
+// HookError is returned when a hook fails, it wraps the error of the hook.
+type HookError struct {
+	Hook string // The name of the hook method.
+	Type reflect.Type
+	Err  error
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("%v: %v.%s: %v", ErrHook, e.Type, e.Hook, e.Err)
+}
+
+// Is makes `HookError` match `ErrHook` and `ErrGlue`.
+func (e *HookError) Is(target error) bool {
+	return target == ErrHook || target == ErrGlue
+}
+
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// beforeHooks calls the hooks before gluing, `dst` and `src` are pointers to
+// struct.
+func beforeHooks(dst, src interface{}, options *glueOptions) error {
+	if h, ok := src.(BeforeGlueOuter); ok && options.FavorSource {
+		if err := h.BeforeGlueOut(dst); err != nil {
+			return &HookError{"BeforeGlueOut", reflect.TypeOf(src), err}
+		}
+	}
+	if h, ok := dst.(BeforeGluer); ok {
+		if err := h.BeforeGlue(src); err != nil {
+			return &HookError{"BeforeGlue", reflect.TypeOf(dst), err}
+		}
+	}
+	return nil
+}
+
+// afterHooks calls the hooks after gluing.
+func afterHooks(dst, src interface{}, options *glueOptions) error {
+	if h, ok := dst.(AfterGluer); ok {
+		if err := h.AfterGlue(src); err != nil {
+			return &HookError{"AfterGlue", reflect.TypeOf(dst), err}
+		}
+	}
+	if h, ok := src.(AfterGlueOuter); ok && options.FavorSource {
+		if err := h.AfterGlueOut(dst); err != nil {
+			return &HookError{"AfterGlueOut", reflect.TypeOf(src), err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package glue
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Mapping is an explicit field mapping between two struct types, it serves the
+// case neither of the structures can be tagged. Once registered, `Glue`
+// consults the mapping for the type pair in place of tags, fields that are not
+// mentioned by the mapping are matched by name.
+type Mapping struct {
+	dst, src reflect.Type
+	fields   []*mappedField
+	ignores  map[string]bool
+	err      error // the first error met when building the mapping.
+}
+
+// mappedField is an explicit mapping from a src field path to a dst field path.
+type mappedField struct {
+	DstPath, SrcPath string
+	DstIndex         []int
+	SrcIndex         []int
+	DstType, SrcType reflect.Type
+	Conv             reflect.Value
+}
+
+var (
+	mappingLock sync.RWMutex
+	mappings    = make(map[typeMapKey]*Mapping, 8)
+)
+
+// NewMapping starts a mapping from src type to dst type, the types are given
+// as hints like `RegConv` does, either a struct or a pointer to struct.
+func NewMapping(tDst, tSrc interface{}) *Mapping {
+	m := &Mapping{
+		ignores: make(map[string]bool),
+	}
+	m.dst = structType(tDst)
+	m.src = structType(tSrc)
+	if m.dst == nil || m.src == nil {
+		m.err = ErrNotStruct
+	}
+	return m
+}
+
+// Field maps the field at `srcPath` to the field at `dstPath`, a path is the
+// field names joined by dot, ex: `Meta.Owner.ID`.
+func (m *Mapping) Field(dstPath, srcPath string) *Mapping {
+	if m.err != nil {
+		return m
+	}
+	mf := m.field(dstPath)
+	if mf == nil {
+		mf = &mappedField{DstPath: dstPath}
+		m.fields = append(m.fields, mf)
+	}
+	mf.SrcPath = srcPath
+	return m
+}
+
+// Ignore excludes dst fields from being copied.
+func (m *Mapping) Ignore(dstPaths ...string) *Mapping {
+	if m.err != nil {
+		return m
+	}
+	for _, path := range dstPaths {
+		if _, _, ok := resolvePath(m.dst, path); !ok {
+			m.err = fmt.Errorf("%w: %q of %v", ErrUnknownField, path, m.dst)
+			return m
+		}
+		m.ignores[path] = true
+	}
+	return m
+}
+
+// Convert uses `converter` for the dst field at `dstPath` only, the converter
+// takes the value of src field and outputs the value of dst field, the src
+// field is the one given by `Field` or has the same name.
+func (m *Mapping) Convert(dstPath string, converter interface{}) *Mapping {
+	if m.err != nil {
+		return m
+	}
+	mf := m.field(dstPath)
+	if mf == nil {
+		mf = &mappedField{DstPath: dstPath, SrcPath: dstPath}
+		m.fields = append(m.fields, mf)
+	}
+	mf.Conv = reflect.ValueOf(converter)
+	return m
+}
+
+// Register validates the mapping and makes `Glue` use it for the type pair, it
+// replaces the previous mapping of the same pair. A copy of the mapping is
+// registered, changing `m` afterwards takes no effect until it is registered
+// again.
+// `Register` returns `ErrUnknownField` if a path does not refer to an exported
+// field and converter errors the same as `RegConv`.
+func (m *Mapping) Register() error {
+	if m.err != nil {
+		return m.err
+	}
+	m = m.clone()
+	for _, mf := range m.fields {
+		var ok bool
+		mf.DstIndex, mf.DstType, ok = resolvePath(m.dst, mf.DstPath)
+		if !ok {
+			return fmt.Errorf("%w: %q of %v", ErrUnknownField, mf.DstPath, m.dst)
+		}
+		mf.SrcIndex, mf.SrcType, ok = resolvePath(m.src, mf.SrcPath)
+		if !ok {
+			return fmt.Errorf("%w: %q of %v", ErrUnknownField, mf.SrcPath, m.src)
+		}
+		if mf.Conv.IsValid() {
+			err := checkConverter(mf.Conv, mf.DstType, mf.SrcType)
+			if err != nil {
+				return fmt.Errorf("%w: field %q", err, mf.DstPath)
+			}
+		}
+	}
+
+	mappingLock.Lock()
+	defer mappingLock.Unlock()
+	mk := typeMapKey{
+		Dst: m.dst,
+		Src: m.src,
+	}
+	mappings[mk] = m
+	resetPlans()
+	return nil
+}
+
+// clone copies the mapping so that the copy is not affected by the builder.
+func (m *Mapping) clone() *Mapping {
+	c := &Mapping{
+		dst:     m.dst,
+		src:     m.src,
+		fields:  make([]*mappedField, len(m.fields)),
+		ignores: make(map[string]bool, len(m.ignores)),
+	}
+	for i, mf := range m.fields {
+		cmf := *mf
+		c.fields[i] = &cmf
+	}
+	for path := range m.ignores {
+		c.ignores[path] = true
+	}
+	return c
+}
+
+// MustRegister is the shorthand of `Register` on initialize, it panics if the
+// mapping is invalid.
+func (m *Mapping) MustRegister() bool {
+	err := m.Register()
+	if err != nil {
+		panic(err)
+	}
+	return true
+}
+
+// DeregMapping deregisters the mapping between two types.
+func DeregMapping(tDst, tSrc interface{}) {
+	mappingLock.Lock()
+	defer mappingLock.Unlock()
+	mk := typeMapKey{
+		Dst: structType(tDst),
+		Src: structType(tSrc),
+	}
+	delete(mappings, mk)
+	resetPlans()
+}
+
+func getMapping(dstType, srcType reflect.Type) *Mapping {
+	mk := typeMapKey{
+		Dst: dstType,
+		Src: srcType,
+	}
+	mappingLock.RLock()
+	defer mappingLock.RUnlock()
+	return mappings[mk]
+}
+
+func (m *Mapping) field(dstPath string) *mappedField {
+	for _, mf := range m.fields {
+		if mf.DstPath == dstPath {
+			return mf
+		}
+	}
+	return nil
+}
+
+// buildPlan builds the plan of the mapping, the fields mapped explicitly are
+// copied after the fields matched by name.
+func (m *Mapping) buildPlan(options *glueOptions) *gluePlan {
+	var (
+		plan           = new(gluePlan)
+		seeker, target = m.dst, m.src
+	)
+	if options.FavorSource {
+		seeker, target = m.src, m.dst
+	}
+	for _, sf := range availableFields(seeker, nil) {
+		if m.covers(sf.Name, options.FavorSource) {
+			continue
+		}
+		if options.FavorSource && m.ignores[sf.Name] {
+			continue
+		}
+		fp := &fieldPlan{Name: sf.Name, Path: sf.Name}
+		plan.Fields = append(plan.Fields, fp)
+		if !options.FavorSource {
+			fp.DstPath = pathOf(m.dst, sf.Index)
+		}
+		counterpart, err := lookupField(target, sf.Name)
+		if err != nil {
+			fp.Err = err
+			continue
+		}
+		fp.Dst, fp.Src = counterpart.Index, sf.Index
+		dstType, srcType := counterpart.Type, sf.Type
+		if !options.FavorSource {
+			fp.Dst, fp.Src = fp.Src, fp.Dst
+			dstType, srcType = srcType, dstType
+		}
+		fp.DstPath = pathOf(m.dst, fp.Dst)
+		fp.resolveConv(dstType, srcType)
+	}
+	for _, mf := range m.fields {
+		if m.ignores[mf.DstPath] {
+			continue
+		}
+		fp := &fieldPlan{
+			Name:    mf.DstPath,
+			Path:    mf.DstPath,
+			Dst:     mf.DstIndex,
+			Src:     mf.SrcIndex,
+			Conv:    mf.Conv,
+			DstPath: mf.DstPath,
+		}
+		if options.FavorSource {
+			fp.Path = mf.SrcPath
+		}
+		fp.resolveConv(mf.DstType, mf.SrcType)
+		plan.Fields = append(plan.Fields, fp)
+	}
+	return plan
+}
+
+// covers reports whether the field `name` of the seeking side is handled by the
+// mapping explicitly, or a field nested in it is.
+func (m *Mapping) covers(name string, favorSource bool) bool {
+	if !favorSource && m.ignores[name] {
+		return true
+	}
+	for _, mf := range m.fields {
+		path := mf.DstPath
+		if favorSource {
+			path = mf.SrcPath
+		}
+		// a field of the nested struct is mapped.
+		if path == name || strings.HasPrefix(path, name+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// structType returns the struct type of a hint, which is either a struct or a
+// pointer to struct, it returns nil if the hint is neither.
+func structType(hint interface{}) reflect.Type {
+	t := reflect.TypeOf(hint)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
+
+// resolvePath resolves a field path like `Meta.Owner.ID` in type `t` into the
+// index sequence and the type of the last field, it reports false if any of the
+// fields is missing or unexported.
+func resolvePath(t reflect.Type, path string) ([]int, reflect.Type, bool) {
+	var index []int
+	for _, name := range strings.Split(path, ".") {
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return nil, nil, false
+		}
+		sf, exist := t.FieldByName(name)
+		if !exist || sf.PkgPath != "" {
+			return nil, nil, false
+		}
+		index = append(index, sf.Index...)
+		t = sf.Type
+	}
+	return index, t, true
+}
@@ -0,0 +1,235 @@
+package glue
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// maskPlan returns the plan restricted to the fields of dst selected by
+// options `DoOnly`, `DoExcept` and `DoFieldMask`. Masked plans are built per
+// call on top of the cached plans, since the masks of requests vary without
+// bound.
+func maskPlan(dstType, srcType reflect.Type, options *glueOptions) *gluePlan {
+	base := *options
+	base.Only, base.Except, base.FieldMask = nil, nil, nil
+
+	var (
+		invalid []*fieldPlan
+		only    []string
+		except  []string
+	)
+	canonical := func(
+		out, paths []string,
+		resolve func(reflect.Type, string) ([]int, reflect.Type, bool),
+	) []string {
+		if out == nil {
+			out = make([]string, 0, len(paths))
+		}
+		for _, path := range paths {
+			index, _, ok := resolve(dstType, path)
+			if !ok {
+				invalid = append(invalid, &fieldPlan{
+					Name: path,
+					Path: path,
+					Err: fmt.Errorf(
+						"%w: %q of %v", ErrUnknownField, path, dstType,
+					),
+				})
+				continue
+			}
+			// a promoted field is selected by the path of the plan.
+			out = append(out, pathOf(dstType, index))
+		}
+		return out
+	}
+	if options.Only != nil {
+		only = canonical(only, options.Only, resolvePath)
+	}
+	if options.FieldMask != nil {
+		only = canonical(only, options.FieldMask, resolveProtoPath)
+	}
+	if options.Except != nil {
+		except = canonical(except, options.Except, resolvePath)
+	}
+
+	basePlan := getPlan(dstType, srcType, &base)
+	plan := basePlan.mask(only, except, &base)
+	plan.Fields = append(invalid, plan.Fields...)
+	// the required fields are of the type pair, whatever the masks.
+	plan.Missing, plan.NonZero = basePlan.Missing, basePlan.NonZero
+	plan.resolveErrs()
+	return plan
+}
+
+// mask returns the plan with the fields of dst selected by `only` (nil means
+// all) except those in `except`, the paths are relative to dst. A struct
+// field selected partially is glued field by field with the plan of the
+// nested struct types.
+func (p *gluePlan) mask(only, except []string, options *glueOptions) *gluePlan {
+	masked := &gluePlan{DstType: p.DstType, SrcType: p.SrcType}
+	for _, fp := range p.Fields {
+		var onlySubs, exceptSubs []string
+		if only != nil {
+			whole, subs := splitPaths(fp.DstPath, only)
+			if !whole && subs == nil {
+				continue
+			}
+			if !whole {
+				onlySubs = subs
+			}
+		}
+		if except != nil {
+			whole, subs := splitPaths(fp.DstPath, except)
+			if whole {
+				continue
+			}
+			exceptSubs = subs
+		}
+		if onlySubs == nil && exceptSubs == nil || fp.Dst == nil || fp.Src == nil {
+			masked.Fields = append(masked.Fields, fp)
+			continue
+		}
+		masked.Fields = append(
+			masked.Fields, p.maskNested(fp, onlySubs, exceptSubs, options)...,
+		)
+	}
+	return masked
+}
+
+// maskNested glues the struct field `fp` partially, which does not need the
+// field to be glued as a whole, like a pointer to struct from a struct. The
+// field is marked as unsatisfied if its types are not both structs that no
+// converter or transform is set between.
+func (p *gluePlan) maskNested(
+	fp *fieldPlan, only, except []string, options *glueOptions,
+) []*fieldPlan {
+	var (
+		dstType = derefType(p.DstType.FieldByIndex(fp.Dst).Type)
+		srcType = derefType(p.SrcType.FieldByIndex(fp.Src).Type)
+	)
+	if dstType.Kind() != reflect.Struct || srcType.Kind() != reflect.Struct ||
+		fp.Conv.IsValid() || fp.Dynamic || fp.Transform.IsValid() ||
+		fp.Err != nil && !errors.Is(fp.Err, ErrMissingConverter) {
+		partial := *fp
+		partial.Err = fmt.Errorf(
+			"%w: %#v cannot be glued partially", ErrUnsatisfiedField, fp.Name,
+		)
+		return []*fieldPlan{&partial}
+	}
+
+	sub := getPlan(dstType, srcType, options).mask(only, except, options)
+	fields := make([]*fieldPlan, len(sub.Fields))
+	for i, sf := range sub.Fields {
+		nested := *sf
+		nested.Name = fp.Name + "." + sf.Name
+		nested.Path = fp.Path + "." + sf.Path
+		if sf.DstPath != "" {
+			nested.DstPath = fp.DstPath + "." + sf.DstPath
+		}
+		if sf.Dst != nil {
+			nested.Dst = append(append([]int{}, fp.Dst...), sf.Dst...)
+			nested.Src = append(append([]int{}, fp.Src...), sf.Src...)
+		}
+		fields[i] = &nested
+	}
+	return fields
+}
+
+// splitPaths matches `path` against `paths`, `whole` reports that `path` or
+// one of its parents is listed, `subs` are the listed paths under `path`
+// relative to it.
+func splitPaths(path string, paths []string) (whole bool, subs []string) {
+	if path == "" {
+		return false, nil
+	}
+	for _, p := range paths {
+		switch {
+		case p == path || strings.HasPrefix(path, p+"."):
+			return true, nil
+		case strings.HasPrefix(p, path+"."):
+			subs = append(subs, p[len(path)+1:])
+		}
+	}
+	return false, subs
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+// resolveProtoPath is `resolvePath` of a path in proto naming, like
+// `user.display_name`, see `DoFieldMask`.
+func resolveProtoPath(t reflect.Type, path string) ([]int, reflect.Type, bool) {
+	var index []int
+	for _, name := range strings.Split(path, ".") {
+		t = derefType(t)
+		if t.Kind() != reflect.Struct || name == "" {
+			return nil, nil, false
+		}
+		sf, exist := lookupProtoField(t, name)
+		if !exist {
+			return nil, nil, false
+		}
+		index = append(index, sf.Index...)
+		t = sf.Type
+	}
+	return index, t, true
+}
+
+// lookupProtoField finds the exported field of struct `t` by the proto
+// `name`, the field tagged with the name comes first, then the field named
+// as the CamelCase of the name, then the field named so case-insensitively,
+// like `UserID` by `user_id`. The shallowest field is taken on each rank.
+func lookupProtoField(t reflect.Type, name string) (reflect.StructField, bool) {
+	var (
+		camel = snakeToCamel(name)
+		found reflect.StructField
+		rank  = 3
+	)
+	for _, sf := range reflect.VisibleFields(t) {
+		if sf.PkgPath != "" {
+			continue
+		}
+		r := 3
+		switch {
+		case protoName(sf.Tag) == name:
+			r = 0
+		case sf.Name == camel:
+			r = 1
+		case strings.EqualFold(sf.Name, camel):
+			r = 2
+		}
+		if r < rank || r == rank && r < 3 && len(sf.Index) < len(found.Index) {
+			found, rank = sf, r
+		}
+	}
+	return found, rank < 3
+}
+
+// protoName returns the name of the `protobuf` tag, empty if there is none.
+func protoName(tag reflect.StructTag) string {
+	raw, exist := tag.Lookup("protobuf")
+	if !exist {
+		return ""
+	}
+	name, _, _ := parseProtobufTag(raw)
+	return name
+}
+
+// snakeToCamel turns `display_name` into `DisplayName`.
+func snakeToCamel(name string) string {
+	var b strings.Builder
+	for _, word := range strings.Split(name, "_") {
+		if word == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]))
+		b.WriteString(word[1:])
+	}
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package glue
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MergeMode decides how a slice or map field is combined with the value
+// already in dst, see `DoMerge` and the tag attribute `merge`. The zero
+// MergeMode replaces the field as `MergeReplace` does.
+type MergeMode int
+
+const (
+	// MergeReplace replaces the field with the value of src, the default.
+	MergeReplace MergeMode = iota + 1
+	// MergeAppend appends the elements of a src slice to the dst slice.
+	MergeAppend
+	// MergeUnion unions a src map into the dst map, src wins on conflicts.
+	MergeUnion
+	// MergeUnionKeep unions a src map into the dst map, dst wins on conflicts.
+	MergeUnionKeep
+)
+
+// The names of modes in tag attribute `merge`.
+var mergeModes = map[string]MergeMode{
+	"replace":    MergeReplace,
+	"append":     MergeAppend,
+	"union":      MergeUnion,
+	"union-keep": MergeUnionKeep,
+}
+
+// appliesTo reports whether the mode merges values of kind `k`.
+func (m MergeMode) appliesTo(k reflect.Kind) bool {
+	switch m {
+	case MergeAppend:
+		return k == reflect.Slice
+	case MergeUnion, MergeUnionKeep:
+		return k == reflect.Map
+	default:
+		return true
+	}
+}
+
+// resolveMerge sets the merge mode from the tag of the field, the field is
+// marked as invalid if the mode does not apply to `dstType`.
+func (fp *fieldPlan) resolveMerge(mode MergeMode, dstType reflect.Type) {
+	if !mode.appliesTo(dstType.Kind()) {
+		fp.Err = fmt.Errorf(
+			"%w: merge mode of %#v does not apply to %v", ErrInvalidTag, fp.Name,
+			dstType,
+		)
+		return
+	}
+	fp.Merge = mode
+}
+
+// mergeMode returns the mode merging the field of kind `k`, the attribute of
+// the tag takes precedence over the options.
+func (fp *fieldPlan) mergeMode(k reflect.Kind, options *glueOptions) MergeMode {
+	mode := fp.Merge
+	switch {
+	case mode != 0:
+	case k == reflect.Slice:
+		mode = options.SliceMerge
+	case k == reflect.Map:
+		mode = options.MapMerge
+	}
+	if mode == 0 {
+		return MergeReplace
+	}
+	return mode
+}
+
+// mergeValue combines `src` into `dst` by `mode` as a new slice or map, so
+// that neither of them is modified, `dst` is returned as is if `src` is
+// empty.
+func mergeValue(dst, src reflect.Value, mode MergeMode) reflect.Value {
+	switch mode {
+	case MergeAppend:
+		if src.Len() == 0 {
+			return dst
+		}
+		out := reflect.MakeSlice(dst.Type(), 0, dst.Len()+src.Len())
+		out = reflect.AppendSlice(out, dst)
+		return reflect.AppendSlice(out, src)
+	case MergeUnion, MergeUnionKeep:
+		if src.Len() == 0 {
+			return dst
+		}
+		out := reflect.MakeMapWithSize(dst.Type(), dst.Len()+src.Len())
+		first, second := dst, src
+		if mode == MergeUnionKeep {
+			first, second = src, dst
+		}
+		for _, m := range []reflect.Value{first, second} {
+			iter := m.MapRange()
+			for iter.Next() {
+				out.SetMapIndex(iter.Key(), iter.Value())
+			}
+		}
+		return out
+	}
+	return src
+}
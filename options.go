@@ -1,9 +1,47 @@
 package glue
 
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
 // The options control how `Glue` behaves.
 type glueOptions struct {
 	FavorSource bool
 	Strict      bool
+	NilAsZero   bool
+	Overwrite   OverwritePolicy
+	SliceMerge  MergeMode
+	MapMerge    MergeMode
+	FieldHooks  []FieldHook
+	Parallel    int      // The number of workers gluing elements.
+	Unordered   bool     // Stream in the order of completion.
+	UnsafeCopy  bool     // Copy runs of identical fields as memory moves.
+	Reverse     bool     // Invert the plan of the opposite direction.
+	TagKeys     []string // nil means the `glue` tag.
+	tagSig      string   // cache key of `TagKeys`.
+	Only        []string // The paths of dst to glue, nil means all.
+	Except      []string // The paths of dst not to glue.
+	FieldMask   []string // The paths of dst to glue in proto naming.
+	Groups      []string // The active groups, see `DoGroups`.
+	groupSig    string   // cache key of `Groups`.
+}
+
+// noOptions is shared by the calls without option, it is never modified.
+var noOptions glueOptions
+
+// newOptions applies `opts` in order, calls without option share `noOptions`
+// so that they do not allocate.
+func newOptions(opts []GlueOption) *glueOptions {
+	if len(opts) == 0 {
+		return &noOptions
+	}
+	options := new(glueOptions)
+	for _, opt := range opts {
+		opt.apply(options)
+	}
+	return options
 }
 
 // The interface all option must implement.
@@ -38,3 +76,243 @@ func DoStrict() GlueOption {
 func (*optStrict) apply(opt *glueOptions) {
 	opt.Strict = true
 }
+
+type optTagKeys struct {
+	keys []string
+	sig  string
+}
+
+// `Glue` reads aliases from the tags in `keys` instead of the `glue` tag, the
+// first key present on a field takes precedence. Tags of `json`, `db` and
+// `protobuf` are understood, other keys are parsed in the form of `name,opts`.
+// Both structures are read with the same keys, so a field is also matched by
+// the alias of its counterpart.
+func DoTagKeys(keys ...string) GlueOption {
+	return &optTagKeys{
+		keys: keys,
+		sig:  strings.Join(keys, ","),
+	}
+}
+
+func (o *optTagKeys) apply(opt *glueOptions) {
+	opt.TagKeys = o.keys
+	opt.tagSig = o.sig
+}
+
+type optNilAsZero struct{}
+
+// singleton
+var optNilZero = &optNilAsZero{}
+
+// A source field behind a nil embedded pointer is treated as zero value, the
+// destination field is zeroed instead of left untouched.
+func DoNilAsZero() GlueOption {
+	return optNilZero
+}
+
+func (*optNilAsZero) apply(opt *glueOptions) {
+	opt.NilAsZero = true
+}
+
+type optFieldHook struct {
+	hook FieldHook
+}
+
+// `Glue` calls `hook` on every field it is about to write, hooks are called in
+// the order they are given, see `FieldHook`.
+func DoFieldHook(hook FieldHook) GlueOption {
+	return &optFieldHook{hook: hook}
+}
+
+func (o *optFieldHook) apply(opt *glueOptions) {
+	opt.FieldHooks = append(opt.FieldHooks, o.hook)
+}
+
+type optParallel struct {
+	n int
+}
+
+// `GlueSlice` and `Stream` glue elements by a pool of `n` workers, `n` less
+// than 2 means sequentially.
+func DoParallel(n int) GlueOption {
+	return &optParallel{n: n}
+}
+
+func (o *optParallel) apply(opt *glueOptions) {
+	opt.Parallel = o.n
+}
+
+type optUnordered struct{}
+
+// singleton
+var optUnord = &optUnordered{}
+
+// `Stream` sends destinations as soon as they are glued instead of in the
+// order of sources, it only matters with `DoParallel`.
+func DoUnordered() GlueOption {
+	return optUnord
+}
+
+func (*optUnordered) apply(opt *glueOptions) {
+	opt.Unordered = true
+}
+
+type optUnsafeCopy struct{}
+
+// singleton
+var optUnsafe = &optUnsafeCopy{}
+
+// `Glue` copies runs of consecutive fields that have identical types and are
+// contiguous in memory on both sides as single memory moves, instead of
+// setting them one by one. Pointers are still copied with write barriers, it
+// is ignored with `DoFieldHook`, `DoOverwrite` other than `OverwriteAlways`
+// and `DoMerge`, which decide field by field.
+func DoUnsafeCopy() GlueOption {
+	return optUnsafe
+}
+
+func (*optUnsafeCopy) apply(opt *glueOptions) {
+	opt.UnsafeCopy = true
+}
+
+type optReverse struct{}
+
+// singleton
+var optRev = &optReverse{}
+
+// `Glue` inverts the plan of gluing dst into src, see `GlueReverse`.
+func DoReverse() GlueOption {
+	return optRev
+}
+
+func (*optReverse) apply(opt *glueOptions) {
+	opt.Reverse = true
+}
+
+type optOverwrite struct {
+	policy OverwritePolicy
+}
+
+// `Glue` writes a destination field only if `policy` allows, the zero-ness
+// of a field is decided as a whole: a struct is zero if all of its fields are,
+// a slice or map is zero if it is nil or empty.
+func DoOverwrite(policy OverwritePolicy) GlueOption {
+	return &optOverwrite{policy: policy}
+}
+
+func (o *optOverwrite) apply(opt *glueOptions) {
+	opt.Overwrite = o.policy
+}
+
+type optMerge struct {
+	modes []MergeMode
+}
+
+// `Glue` merges slice and map fields into the values already in dst by
+// `modes` instead of replacing them, each mode applies to the kind it merges:
+// `MergeAppend` to slices, `MergeUnion` and `MergeUnionKeep` to maps and
+// `MergeReplace` to both, a later mode overrides the former. The attribute
+// `merge` of a `glue` tag takes precedence for the field.
+func DoMerge(modes ...MergeMode) GlueOption {
+	return &optMerge{modes: modes}
+}
+
+func (o *optMerge) apply(opt *glueOptions) {
+	for _, mode := range o.modes {
+		if mode.appliesTo(reflect.Slice) {
+			opt.SliceMerge = mode
+		}
+		if mode.appliesTo(reflect.Map) {
+			opt.MapMerge = mode
+		}
+	}
+}
+
+type optOnly struct {
+	paths []string
+}
+
+// `Glue` only writes the fields of destination at `paths`, a path like
+// `Address.City` selects a field of a nested struct, which is then glued field
+// by field. The paths are validated against the destination type, an unknown
+// path is returned as `ErrUnknownField` whether strict or not. Given more than
+// once, the paths add up, no path means no field.
+func DoOnly(paths ...string) GlueOption {
+	return &optOnly{paths: paths}
+}
+
+func (o *optOnly) apply(opt *glueOptions) {
+	if opt.Only == nil {
+		opt.Only = []string{}
+	}
+	opt.Only = append(opt.Only, o.paths...)
+}
+
+type optExcept struct {
+	paths []string
+}
+
+// `Glue` leaves the fields of destination at `paths` untouched, the paths are
+// those of `DoOnly` and exclude from the fields it selects.
+func DoExcept(paths ...string) GlueOption {
+	return &optExcept{paths: paths}
+}
+
+func (o *optExcept) apply(opt *glueOptions) {
+	opt.Except = append(opt.Except, o.paths...)
+}
+
+type optFieldMask struct {
+	paths []string
+}
+
+// `Glue` only writes the fields of destination at `paths` of a protobuf
+// `FieldMask`, like `user.display_name`, see `DoOnly`. Each name in a path
+// matches the field of the same `protobuf` tag name, or the field named as
+// the CamelCase of the snake_case name, case-insensitively. Given more than
+// once, the paths add up together with those of `DoOnly`.
+func DoFieldMask(paths ...string) GlueOption {
+	return &optFieldMask{paths: paths}
+}
+
+func (o *optFieldMask) apply(opt *glueOptions) {
+	if opt.FieldMask == nil {
+		opt.FieldMask = []string{}
+	}
+	opt.FieldMask = append(opt.FieldMask, o.paths...)
+}
+
+type optGroups struct {
+	groups []string
+}
+
+// `Glue` activates `groups`, a field tagged with groups like
+// `glue:"Email,groups=admin|owner"` is only glued if one of its groups is
+// active, otherwise it is ignored as if tagged with `-`. Fields without groups
+// are always glued. Given more than once, the groups add up.
+func DoGroups(groups ...string) GlueOption {
+	return &optGroups{groups: groups}
+}
+
+func (o *optGroups) apply(opt *glueOptions) {
+	groups := append(append([]string{}, opt.Groups...), o.groups...)
+	sort.Strings(groups)
+	opt.Groups = groups[:0]
+	for i, group := range groups {
+		if i == 0 || group != groups[i-1] {
+			opt.Groups = append(opt.Groups, group)
+		}
+	}
+	opt.groupSig = strings.Join(opt.Groups, "|")
+}
+
+// inGroups reports whether one of `groups` is active.
+func (opt *glueOptions) inGroups(groups []string) bool {
+	for _, group := range groups {
+		i := sort.SearchStrings(opt.Groups, group)
+		if i < len(opt.Groups) && opt.Groups[i] == group {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package glue
+
+import "reflect"
+
+// OverwritePolicy decides whether `Glue` writes a destination field, see
+// `DoOverwrite`.
+type OverwritePolicy int
+
+const (
+	// OverwriteAlways writes every matched field, the default.
+	OverwriteAlways OverwritePolicy = iota
+	// OverwriteOnlyIfDstZero only writes the destination fields that are zero,
+	// which fills the defaults of dst from src.
+	OverwriteOnlyIfDstZero
+	// OverwriteOnlyIfSrcNonZero only writes the fields whose source is not
+	// zero, which patches dst with what src sets.
+	OverwriteOnlyIfSrcNonZero
+)
+
+// allows reports whether the policy writes `dstField` from `srcField`,
+// `srcOk` false means src is behind a nil pointer, which is zero.
+func (p OverwritePolicy) allows(dstField, srcField reflect.Value, srcOk bool) bool {
+	switch p {
+	case OverwriteOnlyIfDstZero:
+		return isZero(dstField)
+	case OverwriteOnlyIfSrcNonZero:
+		return srcOk && !isZero(srcField)
+	default:
+		return true
+	}
+}
+
+// isZero reports whether `v` is the zero value, a struct is zero if all of
+// its fields are, an empty slice or map is zero as well as nil.
+func isZero(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
@@ -0,0 +1,575 @@
+package glue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// fieldPlan describes how one field is copied from src to dst.
+type fieldPlan struct {
+	Name string // The alias/path of the field that seeks its counterpart.
+	Path string // The path of the seeking field in its own struct.
+	Dst  []int  // index sequence of the field in dst struct.
+	Src  []int  // index sequence of the field in src struct.
+	Conv reflect.Value
+	Err  error // Why the field cannot be copied, nil if it can.
+	// The transform applied to the value before written.
+	Transform reflect.Value
+	// The merge mode from the tag, zero if not set.
+	Merge MergeMode
+	// The path of the field in dst, for field hooks.
+	DstPath string
+	// The src field is an interface, the dynamic value is converted to `DstType`
+	// on copy.
+	Dynamic bool
+	DstType reflect.Type
+	// The dst field is an interface, the src struct is glued into a new value
+	// of the registered concrete type, see `RegConcrete`.
+	Concrete reflect.Type
+}
+
+// gluePlan is the list of fields to copy between two struct types.
+type gluePlan struct {
+	Fields           []*fieldPlan
+	DstType, SrcType reflect.Type
+	Missing          []string        // required fields of dst without counterpart.
+	NonZero          []requiredField // fields of dst that must be non-zero.
+
+	// the results of `firstErr` in relaxed and strict mode.
+	errs   [2]error
+	panics [2]bool
+
+	runsOnce sync.Once
+	runs     []copyRun // see `copyRuns`.
+}
+
+// planKey identifies a plan by the type pair and the options the plan depends
+// on.
+type planKey struct {
+	Dst, Src    reflect.Type
+	FavorSource bool
+	Reverse     bool
+	Tags        string // `glueOptions.tagSig`
+	Groups      string // `glueOptions.groupSig`
+}
+
+var (
+	planLock  sync.RWMutex
+	planGen   uint64 // bumped on every reset, a stale plan is not cached.
+	planCache = make(map[planKey]*gluePlan, 32)
+)
+
+// getPlan returns the plan between `dstType` and `srcType`, plans are cached
+// until a converter, transform or mapping is (de)registered. Plans are shared
+// and must not be modified. A plan masked by `DoOnly`, `DoExcept` or
+// `DoFieldMask` is built on every call.
+func getPlan(dstType, srcType reflect.Type, options *glueOptions) *gluePlan {
+	if options.Only != nil || options.Except != nil || options.FieldMask != nil {
+		return maskPlan(dstType, srcType, options)
+	}
+	key := planKey{
+		Dst:         dstType,
+		Src:         srcType,
+		FavorSource: options.FavorSource,
+		Reverse:     options.Reverse,
+		Tags:        options.tagSig,
+		Groups:      options.groupSig,
+	}
+	planLock.RLock()
+	plan, exist := planCache[key]
+	gen := planGen
+	planLock.RUnlock()
+	if exist {
+		return plan
+	}
+
+	if options.Reverse {
+		fwd := *options
+		fwd.Reverse = false
+		plan = getPlan(srcType, dstType, &fwd).reverse(dstType, srcType)
+	} else {
+		plan = buildPlan(dstType, srcType, options)
+	}
+	plan.DstType, plan.SrcType = dstType, srcType
+	plan.resolveRequired(options)
+	plan.resolveErrs()
+	planLock.Lock()
+	if gen == planGen {
+		planCache[key] = plan
+	}
+	planLock.Unlock()
+	return plan
+}
+
+// resetPlans drops the cached plans, it is called whenever the registries
+// plans are built from change.
+func resetPlans() {
+	planLock.Lock()
+	defer planLock.Unlock()
+	planGen++
+	planCache = make(map[planKey]*gluePlan, 32)
+}
+
+// buildPlan resolves the counterparts of fields between `dstType` and
+// `srcType`, fields that cannot be satisfied are kept in the plan with the
+// reason.
+func buildPlan(dstType, srcType reflect.Type, options *glueOptions) *gluePlan {
+	if mp := getMapping(dstType, srcType); mp != nil {
+		return mp.buildPlan(options)
+	}
+
+	var (
+		plan   = new(gluePlan)
+		fAttrs *typeAttr
+	)
+	if options.FavorSource {
+		fAttrs = getTypeAttr(srcType, options)
+	} else {
+		fAttrs = getTypeAttr(dstType, options)
+	}
+
+	for _, fa := range fAttrs.FieldAttrs {
+		var (
+			dstFieldMeta, srcFieldMeta reflect.StructField
+			err                        error
+		)
+		fp := &fieldPlan{Name: fa.Alias, Path: fa.FieldMeta.Name}
+		plan.Fields = append(plan.Fields, fp)
+		if !options.FavorSource {
+			// an unsatisfied field is still selected by the masks.
+			fp.DstPath = pathOf(dstType, fa.FieldMeta.Index)
+		}
+		if fa.Err != nil {
+			fp.Err = fa.Err
+			continue
+		}
+		if options.FavorSource {
+			srcFieldMeta = fa.FieldMeta
+			dstFieldMeta, err = lookupCounterpart(dstType, fa.Alias, options)
+		} else {
+			dstFieldMeta = fa.FieldMeta
+			srcFieldMeta, err = lookupCounterpart(srcType, fa.Alias, options)
+		}
+		if err != nil {
+			fp.Err = err
+			continue
+		}
+		fp.Dst = dstFieldMeta.Index
+		fp.Src = srcFieldMeta.Index
+		fp.DstPath = pathOf(dstType, fp.Dst)
+		fp.resolveConv(dstFieldMeta.Type, srcFieldMeta.Type)
+		if fp.Err == nil && fa.Transform != "" {
+			fp.resolveTransform(fa.Transform, dstFieldMeta.Type)
+		}
+		if fp.Err == nil && fa.Merge != 0 {
+			fp.resolveMerge(fa.Merge, dstFieldMeta.Type)
+		}
+	}
+	return plan
+}
+
+// firstErr returns the first error that stops `Glue`, an invalid tag always
+// panics, a broken transform, an unknown path of mask or a required field
+// without counterpart is always returned, other errors are only returned in
+// strict mode. It is resolved once by `resolveErrs`.
+func (p *gluePlan) firstErr(strict bool) error {
+	mode := 0
+	if strict {
+		mode = 1
+	}
+	if p.panics[mode] {
+		panic(p.errs[mode])
+	}
+	return p.errs[mode]
+}
+
+// resolveErrs finds the errors `firstErr` returns in both modes, it is called
+// once the plan is complete.
+func (p *gluePlan) resolveErrs() {
+	for mode, strict := range []bool{false, true} {
+		p.panics[mode], p.errs[mode] = p.scanErrs(strict)
+	}
+}
+
+// scanErrs returns the first error that stops `Glue`, `panics` reports that
+// the error is raised as a panic.
+func (p *gluePlan) scanErrs(strict bool) (panics bool, err error) {
+	for _, fp := range p.Fields {
+		if fp.Err == nil {
+			continue
+		}
+		if errors.Is(fp.Err, ErrInvalidTag) {
+			return true, fp.Err
+		}
+		if strict || errors.Is(fp.Err, ErrUnknownTransform) ||
+			errors.Is(fp.Err, ErrIncompatSignature) ||
+			errors.Is(fp.Err, ErrUnknownField) {
+			return false, fp.Err
+		}
+	}
+	if len(p.Missing) > 0 {
+		return false, &RequiredError{Type: p.DstType, Missing: p.Missing}
+	}
+	return false, nil
+}
+
+// resolveConv looks up the registered converter if the two types are not
+// strictly equal, the field is marked as unsatisfied if there is no way to
+// convert.
+// A src struct is glued into the concrete type registered for an interface
+// type of dst, a src type implementing it is otherwise assigned directly, an
+// interface type of src is resolved on copy by its dynamic value.
+func (fp *fieldPlan) resolveConv(dstType, srcType reflect.Type) {
+	if dstType == srcType || fp.Conv.IsValid() {
+		return
+	}
+	fconv, exist := lookupConv(dstType, srcType)
+	concrete := lookupConcrete(dstType, srcType)
+	switch {
+	case exist:
+		fp.Conv = fconv
+	case concrete != nil:
+		fp.Concrete = concrete
+	case dstType.Kind() == reflect.Interface && srcType.Implements(dstType):
+	case srcType.Kind() == reflect.Interface:
+		fp.Dynamic = true
+		fp.DstType = dstType
+	default:
+		fp.Err = fmt.Errorf(
+			"%w: %#v from %v to %v", ErrMissingConverter, fp.Name, srcType, dstType,
+		)
+	}
+}
+
+func lookupConv(dstType, srcType reflect.Type) (reflect.Value, bool) {
+	mk := typeMapKey{
+		Dst: dstType,
+		Src: srcType,
+	}
+	convLock.RLock()
+	fconv, exist := typeMap[mk]
+	convLock.RUnlock()
+	return fconv, exist
+}
+
+// convertDynamic converts the dynamic value of an interface to `dstType`, it
+// reports false if the value is nil or cannot be converted.
+func convertDynamic(
+	ctx context.Context, src reflect.Value, dstType reflect.Type,
+) (reflect.Value, bool, error) {
+	elem := src.Elem()
+	if !elem.IsValid() {
+		return elem, false, nil
+	}
+	if elem.Type().AssignableTo(dstType) {
+		return elem, true, nil
+	}
+	fconv, exist := lookupConv(dstType, elem.Type())
+	if !exist {
+		return elem, false, nil
+	}
+	v, err := callConv(ctx, fconv, elem)
+	return v, true, err
+}
+
+// glue copies fields from `srcStruct` to `dstStruct` with the hooks called
+// around, `dst` and `src` are the pointers to them.
+func (p *gluePlan) glue(
+	ctx context.Context, dst, src interface{}, dstStruct, srcStruct reflect.Value,
+	options *glueOptions,
+) error {
+	if err := beforeHooks(dst, src, options); err != nil {
+		return err
+	}
+	if err := p.run(ctx, dstStruct, srcStruct, options); err != nil {
+		return err
+	}
+	if err := p.checkNonZero(dstStruct); err != nil {
+		return err
+	}
+	return afterHooks(dst, src, options)
+}
+
+// run copies fields from `srcStruct` to `dstStruct` according to the plan.
+// A src field behind a nil embedded pointer is treated as missing, or as zero
+// value with option `DoNilAsZero`, nil embedded pointers of dst are allocated
+// when a field behind them is written.
+// With option `DoUnsafeCopy` the runs of fields laid out identically are
+// copied as a whole, see `copyRun`.
+func (p *gluePlan) run(
+	ctx context.Context, dstStruct, srcStruct reflect.Value, options *glueOptions,
+) error {
+	if options.Strict {
+		if err := p.checkNil(dstStruct, srcStruct, options); err != nil {
+			return err
+		}
+	}
+	runs := p.copyRuns(options)
+	for i := 0; i < len(p.Fields); i++ {
+		fp := p.Fields[i]
+		if fp.Err != nil {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if len(runs) > 0 && runs[0].Start == i {
+			runs[0].copy(dstStruct, srcStruct)
+			i = runs[0].End - 1
+			runs = runs[1:]
+			continue
+		}
+		srcField, srcOk := fieldByIndex(srcStruct, fp.Src, false)
+		if !srcOk && !options.NilAsZero {
+			if options.Strict {
+				return fmt.Errorf(
+					"%w: %#v is behind nil pointer", ErrUnsatisfiedField, fp.Name,
+				)
+			}
+			continue
+		}
+		// `dstField` is the nil pointer on the way if not ok, it is only
+		// allocated if the field is written.
+		dstField, alloc, ok := lookupDst(dstStruct, fp.Dst)
+		if !ok {
+			// a nil pointer to unexported embedded struct cannot be allocated.
+			if options.Strict {
+				return fmt.Errorf(
+					"%w: %#v is not settable", ErrUnsatisfiedField, fp.Name,
+				)
+			}
+			continue
+		}
+		v, write, err := fp.value(ctx, dstField, srcField, srcOk, options)
+		if err != nil {
+			return err
+		}
+		if !write {
+			continue
+		}
+		if alloc {
+			dstField, _ = fieldByIndex(dstStruct, fp.Dst, true)
+		}
+		dstField.Set(v)
+	}
+	return nil
+}
+
+// checkNil returns the error `run` would meet in strict mode on a nil pointer
+// on the way of a field, so that no field is copied if one would fail.
+func (p *gluePlan) checkNil(dstStruct, srcStruct reflect.Value, options *glueOptions) error {
+	for _, fp := range p.Fields {
+		if fp.Err != nil {
+			continue
+		}
+		if _, ok := fieldByIndex(srcStruct, fp.Src, false); !ok && !options.NilAsZero {
+			return fmt.Errorf(
+				"%w: %#v is behind nil pointer", ErrUnsatisfiedField, fp.Name,
+			)
+		}
+		if _, _, ok := lookupDst(dstStruct, fp.Dst); !ok {
+			return fmt.Errorf(
+				"%w: %#v is not settable", ErrUnsatisfiedField, fp.Name,
+			)
+		}
+	}
+	return nil
+}
+
+// value returns the value of `srcField` to write into `dstField`, converted,
+// transformed and passed through the field hooks, it reports false if the
+// field is left untouched. `srcOk` false means src is behind a nil pointer,
+// which is written as zero value.
+func (fp *fieldPlan) value(
+	ctx context.Context, dstField, srcField reflect.Value, srcOk bool,
+	options *glueOptions,
+) (reflect.Value, bool, error) {
+	if !options.Overwrite.allows(dstField, srcField, srcOk) {
+		return srcField, false, nil
+	}
+	if !srcOk {
+		return reflect.Zero(dstField.Type()), true, nil
+	}
+	// reading a field does not require it to be settable.
+	if !srcField.CanInterface() {
+		return srcField, false, nil
+	}
+	var v reflect.Value
+	switch {
+	case fp.Dynamic && srcField.IsNil():
+		if options.NilAsZero {
+			v = reflect.Zero(fp.DstType)
+			break
+		}
+		if options.Strict {
+			return v, false, fmt.Errorf("%w: %#v is nil", ErrUnsatisfiedField, fp.Name)
+		}
+		return v, false, nil
+	case fp.Dynamic:
+		var (
+			ok  bool
+			err error
+		)
+		v, ok, err = convertDynamic(ctx, srcField, fp.DstType)
+		if err != nil {
+			return v, false, &FieldError{Path: fp.DstPath, Err: err}
+		}
+		if ok {
+			break
+		}
+		if options.Strict {
+			return v, false, fmt.Errorf(
+				"%w: %#v holds %v", ErrMissingConverter, fp.Name,
+				srcField.Elem().Type(),
+			)
+		}
+		return v, false, nil
+	case fp.Conv.IsValid():
+		var err error
+		v, err = callConv(ctx, fp.Conv, srcField)
+		if err != nil {
+			return v, false, &FieldError{Path: fp.DstPath, Err: err}
+		}
+	case fp.Concrete != nil:
+		var err error
+		v, err = glueConcrete(ctx, fp.Concrete, dstField.Type(), srcField, options)
+		if err != nil {
+			return v, false, &FieldError{Path: fp.DstPath, Err: err}
+		}
+	default:
+		// the types are identical or assignable, set directly without
+		// boxing the value into an interface.
+		v = srcField
+	}
+	if fp.Transform.IsValid() {
+		v = fp.Transform.Call([]reflect.Value{v})[0]
+	}
+	if mode := fp.mergeMode(dstField.Kind(), options); mode != MergeReplace {
+		v = mergeValue(dstField, v, mode)
+	}
+	write := true
+	for _, hook := range options.FieldHooks {
+		var err error
+		v, write, err = hook(fp.DstPath, dstField, v)
+		if err != nil {
+			return v, false, &FieldError{Path: fp.DstPath, Err: err}
+		}
+		if !write {
+			break
+		}
+	}
+	return v, write, nil
+}
+
+// fieldByIndex is the nil-safe version of `reflect.Value.FieldByIndex`, it
+// reports false if there is a nil pointer to struct on the way, unless `alloc`
+// is set and the pointer can be set, in which case the pointer is allocated.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return v, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// lookupDst finds the field at `index` of `dstStruct` without allocating the
+// nil pointers on the way, in which case the zero value of the field is
+// returned and `alloc` reports they must be allocated to write the field. It
+// reports false if the field cannot be written, like behind a nil pointer to
+// unexported embedded struct, even one that would be reached by allocating.
+func lookupDst(dstStruct reflect.Value, index []int) (field reflect.Value, alloc, ok bool) {
+	v := dstStruct
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() || !canAlloc(v.Type().Elem(), index[i:]) {
+					return v, false, false
+				}
+				ft := dstStruct.Type().FieldByIndex(index).Type
+				return reflect.Zero(ft), true, true
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, false, v.CanSet()
+}
+
+// canAlloc reports whether the field at `index` of a new value of struct `t`
+// can be written, that is there is no pointer to unexported embedded struct on
+// the way, which would be nil and cannot be allocated.
+func canAlloc(t reflect.Type, index []int) bool {
+	for i, x := range index {
+		sf := t.Field(x)
+		if i < len(index)-1 && sf.Type.Kind() == reflect.Ptr && sf.PkgPath != "" {
+			return false
+		}
+		t = derefType(sf.Type)
+	}
+	return true
+}
+
+// promotedPaths lists the paths of the fields named `name` at the shallowest
+// depth they are found in `t`, more than one path means the name is ambiguous,
+// which `reflect.Type.FieldByName` reports as not found.
+func promotedPaths(t reflect.Type, name string) []string {
+	type scan struct {
+		t    reflect.Type
+		path string
+	}
+	var (
+		current = []scan{{t: t}}
+		visited = make(map[reflect.Type]bool)
+	)
+	for len(current) > 0 {
+		var (
+			found []string
+			next  []scan
+		)
+		for _, sc := range current {
+			if visited[sc.t] {
+				continue
+			}
+			for i := 0; i < sc.t.NumField(); i++ {
+				sf := sc.t.Field(i)
+				path := sf.Name
+				if sc.path != "" {
+					path = sc.path + "." + sf.Name
+				}
+				if sf.Name == name {
+					found = append(found, path)
+					continue
+				}
+				if !sf.Anonymous {
+					continue
+				}
+				ft := sf.Type
+				if ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if ft.Kind() == reflect.Struct {
+					next = append(next, scan{t: ft, path: path})
+				}
+			}
+		}
+		if len(found) > 0 {
+			return found
+		}
+		for _, sc := range current {
+			visited[sc.t] = true
+		}
+		current = next
+	}
+	return nil
+}
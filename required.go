@@ -0,0 +1,87 @@
+package glue
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RequiredError lists the required fields of dst that are not satisfied,
+// which are tagged with attribute `required` or `nonzero`. It matches
+// `ErrRequiredField`, and `ErrUnsatisfiedField` if a field has no
+// counterpart.
+type RequiredError struct {
+	Type    reflect.Type // The type of dst.
+	Missing []string     // The paths of the fields without counterpart.
+	Zero    []string     // The paths of the `nonzero` fields left zero.
+}
+
+func (e *RequiredError) Error() string {
+	var problems []string
+	if len(e.Missing) > 0 {
+		problems = append(problems, "no counterpart for "+strings.Join(e.Missing, ", "))
+	}
+	if len(e.Zero) > 0 {
+		problems = append(problems, "left zero "+strings.Join(e.Zero, ", "))
+	}
+	return fmt.Sprintf("%v: %v: %s", ErrRequiredField, e.Type, strings.Join(problems, "; "))
+}
+
+// Is makes `RequiredError` match `ErrRequiredField` and `ErrGlue`, as well as
+// `ErrUnsatisfiedField` if a field has no counterpart.
+func (e *RequiredError) Is(target error) bool {
+	return target == ErrRequiredField || target == ErrGlue ||
+		target == ErrUnsatisfiedField && len(e.Missing) > 0
+}
+
+// requiredField is a field of dst that must be non-zero after gluing.
+type requiredField struct {
+	Path  string
+	Index []int
+}
+
+// resolveRequired finds the required fields of dst that have no counterpart in
+// the plan and the fields that must be non-zero, they are read from the tags
+// of dst whichever side seeks counterparts.
+func (p *gluePlan) resolveRequired(options *glueOptions) {
+	var satisfied map[string]bool
+	for _, fa := range getTypeAttr(p.DstType, options).FieldAttrs {
+		if !fa.Required || fa.Err != nil {
+			continue
+		}
+		if satisfied == nil {
+			satisfied = make(map[string]bool, len(p.Fields))
+			for _, fp := range p.Fields {
+				if fp.Err == nil {
+					satisfied[fp.DstPath] = true
+				}
+			}
+		}
+		path := pathOf(p.DstType, fa.FieldMeta.Index)
+		if !satisfied[path] {
+			p.Missing = append(p.Missing, path)
+		}
+		if fa.NonZero {
+			p.NonZero = append(p.NonZero, requiredField{
+				Path:  path,
+				Index: fa.FieldMeta.Index,
+			})
+		}
+	}
+}
+
+// checkNonZero returns a `*RequiredError` listing the `nonzero` fields left
+// zero in `dstStruct`.
+func (p *gluePlan) checkNonZero(dstStruct reflect.Value) error {
+	var zero []string
+	for _, rf := range p.NonZero {
+		v, ok := fieldByIndex(dstStruct, rf.Index, false)
+		if !ok || isZero(v) {
+			zero = append(zero, rf.Path)
+		}
+	}
+	if len(zero) == 0 {
+		return nil
+	}
+	return &RequiredError{Type: p.DstType, Zero: zero}
+}
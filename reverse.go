@@ -0,0 +1,87 @@
+package glue
+
+import (
+	"reflect"
+)
+
+// GlueReverse glues src back into dst with the plan of `Glue(src, dst)`
+// inverted, so that the aliases, mappings and the side seeking counterparts
+// declared for the forward direction apply in the reverse direction as well.
+// It is the shorthand of `Glue` with option `DoReverse`.
+//
+//	glue.Glue(dto, model)        // DTO declares aliases of Model.
+//	glue.GlueReverse(model, dto) // the same fields are glued back.
+func GlueReverse(dst, src interface{}, opts ...GlueOption) error {
+	// a new slice, `opts` may be shared by the caller.
+	revOpts := make([]GlueOption, len(opts), len(opts)+1)
+	copy(revOpts, opts)
+	return Glue(dst, src, append(revOpts, DoReverse())...)
+}
+
+// RegConvPair registers a converter and its inverse at once, `forward`
+// converts S to D in one of the forms `RegConv` accepts and `backward`
+// converts D back to S, the types are taken from the signatures. The pair is
+// used by `Glue` in both directions, which `DoReverse` relies on to invert a
+// converted field. Each of the pair can be deregistered by `DeregConv`.
+func RegConvPair(forward, backward interface{}) error {
+	vForward := reflect.ValueOf(forward)
+	vBackward := reflect.ValueOf(backward)
+	if vForward.Kind() != reflect.Func || vBackward.Kind() != reflect.Func {
+		return ErrNotFunction
+	}
+	ft := vForward.Type()
+	if ft.NumIn() == 0 || ft.NumOut() == 0 {
+		return ErrIncompatSignature
+	}
+	typeDst, typeSrc := ft.Out(0), ft.In(ft.NumIn()-1)
+	if err := checkConverter(vForward, typeDst, typeSrc); err != nil {
+		return err
+	}
+	if err := checkConverter(vBackward, typeSrc, typeDst); err != nil {
+		return err
+	}
+
+	convLock.Lock()
+	defer convLock.Unlock()
+	typeMap[typeMapKey{Dst: typeDst, Src: typeSrc}] = vForward
+	typeMap[typeMapKey{Dst: typeSrc, Src: typeDst}] = vBackward
+	resetPlans()
+	return nil
+}
+
+// MustRegConvPair is the shorthand of `RegConvPair` on initialize, it panics
+// if the pair is not valid.
+func MustRegConvPair(forward, backward interface{}) bool {
+	err := RegConvPair(forward, backward)
+	if err != nil {
+		panic(err)
+	}
+	return true
+}
+
+// reverse inverts the plan of gluing `srcType` into `dstType`, the fields
+// are paired the same, the converters are looked up in the opposite
+// direction and the transforms are dropped since they cannot be inverted.
+func (p *gluePlan) reverse(dstType, srcType reflect.Type) *gluePlan {
+	rev := &gluePlan{Fields: make([]*fieldPlan, len(p.Fields))}
+	for i, fwd := range p.Fields {
+		fp := &fieldPlan{
+			Name: fwd.Name,
+			Path: fwd.Path,
+			Dst:  fwd.Src,
+			Src:  fwd.Dst,
+		}
+		rev.Fields[i] = fp
+		// a paired field is resolved anew, a converter or transform failing in
+		// forward direction does not matter in reverse.
+		if fp.Dst == nil || fp.Src == nil {
+			fp.Err = fwd.Err
+			continue
+		}
+		fp.DstPath = pathOf(dstType, fp.Dst)
+		fp.resolveConv(
+			dstType.FieldByIndex(fp.Dst).Type, srcType.FieldByIndex(fp.Src).Type,
+		)
+	}
+	return rev
+}
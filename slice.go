@@ -0,0 +1,195 @@
+package glue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// IndexError is the error of one element glued by `GlueSlice`.
+type IndexError struct {
+	Index int
+	Err   error
+}
+
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("index %d: %v", e.Index, e.Err)
+}
+
+func (e *IndexError) Unwrap() error {
+	return e.Err
+}
+
+// SliceError aggregates the errors of elements glued by `GlueSlice`, the
+// errors are sorted by index.
+type SliceError struct {
+	Errs []*IndexError
+}
+
+func (e *SliceError) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf(
+		"%v: %d element(s) failed: %s", ErrGlue, len(e.Errs),
+		strings.Join(msgs, "; "),
+	)
+}
+
+// Is reports whether the error of any element matches `target`.
+func (e *SliceError) Is(target error) bool {
+	for _, err := range e.Errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return target == ErrGlue
+}
+
+// Unwrap returns the errors of elements.
+func (e *SliceError) Unwrap() []error {
+	errs := make([]error, len(e.Errs))
+	for i, err := range e.Errs {
+		errs[i] = err
+	}
+	return errs
+}
+
+// GlueSlice glues every element of srcSlice into a newly allocated slice and
+// stores it to dstSlicePtr, the elements are structs or pointers to struct on
+// both sides. The plan of the element types is built once for all elements,
+// with option `DoParallel` the elements are glued by a pool of workers.
+// A nil element of srcSlice leaves the element of dst zero(nil), the errors of
+// elements are aggregated in a `*SliceError` and the slice is stored anyway.
+func GlueSlice(dstSlicePtr, srcSlice interface{}, opts ...GlueOption) error {
+	return GlueSliceContext(context.Background(), dstSlicePtr, srcSlice, opts...)
+}
+
+// GlueSliceContext is `GlueSlice` with a context, it stops gluing and returns
+// `ctx.Err()` once the context is done, dstSlicePtr is left untouched then.
+func GlueSliceContext(
+	ctx context.Context, dstSlicePtr, srcSlice interface{}, opts ...GlueOption,
+) error {
+	var (
+		vdst = reflect.ValueOf(dstSlicePtr)
+		vsrc = reflect.ValueOf(srcSlice)
+	)
+	if vdst.Kind() != reflect.Ptr || vdst.IsNil() ||
+		vdst.Elem().Kind() != reflect.Slice || vsrc.Kind() != reflect.Slice {
+		return ErrNotSliceOfStruct
+	}
+	dstType, dstIsPtr := elemStructType(vdst.Type().Elem().Elem())
+	srcType, srcIsPtr := elemStructType(vsrc.Type().Elem())
+	if dstType == nil || srcType == nil {
+		return ErrNotSliceOfStruct
+	}
+
+	options := newOptions(opts)
+
+	plan := getPlan(dstType, srcType, options)
+	if err := plan.firstErr(options.Strict); err != nil {
+		return err
+	}
+
+	n := vsrc.Len()
+	out := reflect.MakeSlice(vdst.Elem().Type(), n, n)
+	glueAt := func(i int) error {
+		srcPtr := vsrc.Index(i)
+		if !srcIsPtr {
+			srcPtr = srcPtr.Addr()
+		} else if srcPtr.IsNil() {
+			return nil
+		}
+		dstPtr := out.Index(i)
+		if !dstIsPtr {
+			dstPtr = dstPtr.Addr()
+		} else {
+			dstPtr.Set(reflect.New(dstType))
+		}
+		return plan.glue(
+			ctx, dstPtr.Interface(), srcPtr.Interface(),
+			dstPtr.Elem(), srcPtr.Elem(), options,
+		)
+	}
+	errs, err := forEachIndex(ctx, n, options.Parallel, glueAt)
+	if err != nil {
+		return err
+	}
+	vdst.Elem().Set(out)
+	if len(errs) > 0 {
+		return &SliceError{Errs: errs}
+	}
+	return nil
+}
+
+// forEachIndex calls `fn` on indices [0, n) by `workers` goroutines, it stops
+// once `ctx` is done and returns `ctx.Err()`.
+func forEachIndex(
+	ctx context.Context, n, workers int, fn func(i int) error,
+) ([]*IndexError, error) {
+	var errs []*IndexError
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if err := fn(i); err != nil {
+				errs = append(errs, &IndexError{Index: i, Err: err})
+			}
+		}
+		return errs, nil
+	}
+	if workers > n {
+		workers = n
+	}
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		next int64
+	)
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				i := int(atomic.AddInt64(&next, 1) - 1)
+				if i >= n {
+					return
+				}
+				if err := fn(i); err != nil {
+					mu.Lock()
+					errs = append(errs, &IndexError{Index: i, Err: err})
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(errs, func(i, j int) bool {
+		return errs[i].Index < errs[j].Index
+	})
+	return errs, nil
+}
+
+// elemStructType returns the struct type of a slice element, which is either
+// a struct or a pointer to struct.
+func elemStructType(t reflect.Type) (reflect.Type, bool) {
+	isPtr := t.Kind() == reflect.Ptr
+	if isPtr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, false
+	}
+	return t, isPtr
+}
@@ -0,0 +1,287 @@
+package glue
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// Stream glues every source received from `in` into a new destination and
+// sends it to `out`, the fields are resolved once for the type pair with the
+// same rules as `Glue`.
+// `in` is a channel of struct or pointer to struct, or an iterator in the form
+// of `func() (S, bool)` which returns false when exhausted. `out` is a channel
+// of struct or pointer to struct, it is closed once `in` is exhausted or `ctx`
+// is done, or right away if the stream cannot start, like a plan failing in
+// strict mode. A slow receiver of `out` holds back reading `in`.
+// With option `DoParallel(n)` the sources are glued by a pool of `n` workers,
+// the order of `in` is kept unless option `DoUnordered` is given.
+// The errors are sent to the returned channel, which is closed after `out`:
+// an error of a source is sent as an `*IndexError` telling its sequence number
+// and the destination is dropped, the error of `ctx` stops the stream. The
+// error channel must be drained concurrently with `out`. Nil sources are
+// dropped silently.
+func Stream(ctx context.Context, out, in interface{}, opts ...GlueOption) <-chan error {
+	var (
+		vout = reflect.ValueOf(out)
+		vin  = reflect.ValueOf(in)
+		errs = make(chan error, 1)
+	)
+	options := newOptions(opts)
+	s, err := newStream(ctx, vout, vin, errs, options)
+	if err != nil {
+		// the receivers of `out` are not left waiting.
+		if vout.Kind() == reflect.Chan && !vout.IsNil() &&
+			vout.Type().ChanDir()&reflect.SendDir != 0 {
+			vout.Close()
+		}
+		errs <- err
+		close(errs)
+		return errs
+	}
+	go s.run()
+	return errs
+}
+
+// stream is the state of a running `Stream`.
+type stream struct {
+	ctx              context.Context
+	out, in          reflect.Value
+	errs             chan error
+	options          *glueOptions
+	plan             *gluePlan
+	dstType, srcType reflect.Type
+	dstIsPtr         bool
+	srcIsPtr         bool
+	iterator         bool
+}
+
+// streamItem is a source in flight.
+type streamItem struct {
+	Index int
+	Src   reflect.Value
+	Dst   reflect.Value
+	Err   error
+	Done  chan struct{} // closed once glued, only in ordered mode.
+}
+
+func newStream(
+	ctx context.Context, vout, vin reflect.Value, errs chan error,
+	options *glueOptions,
+) (*stream, error) {
+	s := &stream{
+		ctx:     ctx,
+		out:     vout,
+		in:      vin,
+		errs:    errs,
+		options: options,
+	}
+	if vout.Kind() != reflect.Chan || vout.Type().ChanDir()&reflect.SendDir == 0 {
+		return nil, ErrNotChanOfStruct
+	}
+	s.dstType, s.dstIsPtr = elemStructType(vout.Type().Elem())
+
+	var srcElem reflect.Type
+	switch {
+	case vin.Kind() == reflect.Chan && vin.Type().ChanDir()&reflect.RecvDir != 0:
+		srcElem = vin.Type().Elem()
+	case vin.Kind() == reflect.Func && !vin.IsNil() && vin.Type().NumIn() == 0 &&
+		vin.Type().NumOut() == 2 && vin.Type().Out(1).Kind() == reflect.Bool:
+		srcElem = vin.Type().Out(0)
+		s.iterator = true
+	default:
+		return nil, ErrNotChanOfStruct
+	}
+	s.srcType, s.srcIsPtr = elemStructType(srcElem)
+	if s.dstType == nil || s.srcType == nil {
+		return nil, ErrNotChanOfStruct
+	}
+
+	s.plan = getPlan(s.dstType, s.srcType, options)
+	if err := s.plan.firstErr(options.Strict); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *stream) run() {
+	defer close(s.errs)
+	defer s.out.Close()
+
+	switch {
+	case s.options.Parallel <= 1:
+		s.runSequential()
+	case s.options.Unordered:
+		s.runUnordered()
+	default:
+		s.runOrdered()
+	}
+	if err := s.ctx.Err(); err != nil {
+		s.errs <- err
+	}
+}
+
+func (s *stream) runSequential() {
+	for i := 0; ; i++ {
+		src, ok := s.recv()
+		if !ok {
+			return
+		}
+		item := &streamItem{Index: i, Src: src}
+		s.glue(item)
+		if !s.emit(item) {
+			return
+		}
+	}
+}
+
+func (s *stream) runUnordered() {
+	var (
+		items = make(chan *streamItem)
+		wg    sync.WaitGroup
+	)
+	wg.Add(s.options.Parallel)
+	for w := 0; w < s.options.Parallel; w++ {
+		go func() {
+			defer wg.Done()
+			for item := range items {
+				s.glue(item)
+				if !s.emit(item) {
+					return
+				}
+			}
+		}()
+	}
+	s.feed(items, nil)
+	wg.Wait()
+}
+
+func (s *stream) runOrdered() {
+	var (
+		items = make(chan *streamItem)
+		// in-flight items in the order of input, bounds the workers ahead.
+		order = make(chan *streamItem, s.options.Parallel)
+		wg    sync.WaitGroup
+	)
+	for w := 0; w < s.options.Parallel; w++ {
+		go func() {
+			for item := range items {
+				s.glue(item)
+				close(item.Done)
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for item := range order {
+			<-item.Done
+			if !s.emit(item) {
+				// drain the rest so the feeder is never blocked.
+				for range order {
+				}
+				return
+			}
+		}
+	}()
+	s.feed(items, order)
+	wg.Wait()
+}
+
+// feed receives sources and dispatches them to workers, also to `order` if
+// not nil, it closes the channels once `in` is exhausted.
+func (s *stream) feed(items, order chan *streamItem) {
+	defer close(items)
+	if order != nil {
+		defer close(order)
+	}
+	for i := 0; ; i++ {
+		src, ok := s.recv()
+		if !ok {
+			return
+		}
+		item := &streamItem{Index: i, Src: src}
+		if order != nil {
+			item.Done = make(chan struct{})
+			select {
+			case order <- item:
+			case <-s.ctx.Done():
+				return
+			}
+		}
+		select {
+		case items <- item:
+		case <-s.ctx.Done():
+			if item.Done != nil {
+				close(item.Done)
+			}
+			return
+		}
+	}
+}
+
+// recv receives the next source, it reports false once the input is
+// exhausted or the context is done.
+func (s *stream) recv() (reflect.Value, bool) {
+	if s.ctx.Err() != nil {
+		return reflect.Value{}, false
+	}
+	if s.iterator {
+		ret := s.in.Call(nil)
+		return ret[0], ret[1].Bool()
+	}
+	chosen, v, ok := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: s.in},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(s.ctx.Done())},
+	})
+	return v, chosen == 0 && ok
+}
+
+// glue glues the source of `item` into a new destination.
+func (s *stream) glue(item *streamItem) {
+	srcPtr := item.Src
+	if !s.srcIsPtr {
+		srcPtr = reflect.New(s.srcType)
+		srcPtr.Elem().Set(item.Src)
+	} else if srcPtr.IsNil() {
+		return
+	}
+	dstPtr := reflect.New(s.dstType)
+	err := s.plan.glue(
+		s.ctx, dstPtr.Interface(), srcPtr.Interface(),
+		dstPtr.Elem(), srcPtr.Elem(), s.options,
+	)
+	if err != nil {
+		item.Err = &IndexError{Index: item.Index, Err: err}
+		return
+	}
+	item.Dst = dstPtr
+	if !s.dstIsPtr {
+		item.Dst = dstPtr.Elem()
+	}
+}
+
+// emit sends the result of `item` to `out` or the error channel, it reports
+// false if the context is done.
+func (s *stream) emit(item *streamItem) bool {
+	if s.ctx.Err() != nil {
+		return false
+	}
+	if item.Err != nil {
+		select {
+		case s.errs <- item.Err:
+			return true
+		case <-s.ctx.Done():
+			return false
+		}
+	}
+	if !item.Dst.IsValid() {
+		// nil source.
+		return true
+	}
+	chosen, _, _ := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectSend, Chan: s.out, Send: item.Dst},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(s.ctx.Done())},
+	})
+	return chosen == 0
+}
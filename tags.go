@@ -0,0 +1,133 @@
+package glue
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// tagParser extracts the alias from the value of a struct tag, `ignore`
+// reports that the tag explicitly excludes the field, an empty alias means the
+// tag does not rename the field.
+type tagParser func(tag string) (alias string, ignore bool, err error)
+
+// The parsers of well known tag keys, keys not listed here are parsed with
+// `parseNameTag`.
+var tagParsers = map[string]tagParser{
+	glueTagKey: parseGlueTag,
+	"json":     parseNameTag,
+	"db":       parseNameTag,
+	"protobuf": parseProtobufTag,
+}
+
+// glueTag is the parsed `glue` tag, in the form of `alias,attr1,attr2=value`.
+type glueTag struct {
+	Alias     string
+	Ignore    bool
+	Transform string // The name of the transform applied to the field.
+	Merge     MergeMode
+	Groups    []string // The groups the field is visible to.
+	Required  bool
+	NonZero   bool
+}
+
+// parseGlueTag parses the tag of `glue` itself as a `tagParser`.
+func parseGlueTag(tag string) (string, bool, error) {
+	gt, err := parseGlueAttrs(tag)
+	return gt.Alias, gt.Ignore, err
+}
+
+// parseGlueAttrs parses the tag of `glue` itself, the tag is either `-` or an
+// optional alias followed by attributes, the alias must be a valid identifier,
+// or identifiers joined by dot to pick a promoted field.
+func parseGlueAttrs(tag string) (glueTag, error) {
+	var gt glueTag
+	if tag == attrIgnr {
+		gt.Ignore = true
+		return gt, nil
+	}
+	attrs := strings.Split(tag, ",")
+	gt.Alias = attrs[0]
+	if !(gt.Alias == "" && len(attrs) > 1) && !isValidPath(gt.Alias) {
+		return gt, fmt.Errorf(
+			"%w: %q is not a valid identifier", ErrInvalidTag, gt.Alias,
+		)
+	}
+	for _, attr := range attrs[1:] {
+		key, value := attr, ""
+		if i := strings.IndexByte(attr, '='); i >= 0 {
+			key, value = attr[:i], attr[i+1:]
+		}
+		switch {
+		case key == attrTransform && value != "":
+			gt.Transform = value
+		case key == attrMerge && mergeModes[value] != 0:
+			gt.Merge = mergeModes[value]
+		case key == attrGroups && isValidGroups(value):
+			gt.Groups = strings.Split(value, "|")
+		case key == attrRequired && value == "":
+			gt.Required = true
+		case key == attrNonZero && value == "":
+			gt.NonZero = true
+		default:
+			return gt, fmt.Errorf("%w: unknown attribute %q", ErrInvalidTag, attr)
+		}
+	}
+	return gt, nil
+}
+
+// isValidGroups reports whether `value` is a list of group names separated by
+// `|`, like `admin|owner`.
+func isValidGroups(value string) bool {
+	for _, group := range strings.Split(value, "|") {
+		if group == "" || strings.ContainsAny(group, " ,=") {
+			return false
+		}
+	}
+	return true
+}
+
+// parseNameTag parses tags in the form of `name,opt1,opt2`, which is the
+// convention of `encoding/json` and most database libraries.
+func parseNameTag(tag string) (string, bool, error) {
+	if tag == attrIgnr {
+		return "", true, nil
+	}
+	if i := strings.IndexByte(tag, ','); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag, false, nil
+}
+
+// parseProtobufTag parses tags generated by protoc-gen-go, the name is stored
+// in attribute `name=`, ex: `bytes,1,opt,name=user_id,json=userId,proto3`.
+func parseProtobufTag(tag string) (string, bool, error) {
+	for _, attr := range strings.Split(tag, ",") {
+		if strings.HasPrefix(attr, "name=") {
+			return attr[len("name="):], false, nil
+		}
+	}
+	return "", false, nil
+}
+
+// lookupTagAlias reads the alias of a field from the first tag key in `keys`
+// that gives one.
+func lookupTagAlias(
+	tag reflect.StructTag, keys []string,
+) (alias string, ignore bool, err error) {
+	for _, key := range keys {
+		raw, exist := tag.Lookup(key)
+		if !exist {
+			continue
+		}
+		parse, exist := tagParsers[key]
+		if !exist {
+			parse = parseNameTag
+		}
+		alias, ignore, err = parse(raw)
+		if err != nil || ignore || alias != "" {
+			return alias, ignore, err
+		}
+	}
+	return "", false, nil
+}
@@ -0,0 +1,98 @@
+package glue
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// FieldHook is called on every field `Glue` is about to write, `path` is the
+// path of the field in dst, `dst` is the field itself and `src` is the value
+// to write, which is already converted and transformed, it may be the field
+// of src itself and must not be modified. The hook returns the value to write
+// instead, or false to leave the field untouched, an error aborts `Glue`.
+type FieldHook func(path string, dst, src reflect.Value) (reflect.Value, bool, error)
+
+// FieldError is returned when a field fails to be glued by a hook or a
+// converter, it wraps the error of the failed function.
+type FieldError struct {
+	Path string // The path of the field in dst.
+	Err  error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%v: field %q: %v", ErrGlue, e.Path, e.Err)
+}
+
+// Is makes `FieldError` match `ErrGlue`.
+func (e *FieldError) Is(target error) bool {
+	return target == ErrGlue
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	transformLock sync.RWMutex
+	transforms    = make(map[string]reflect.Value, 8)
+)
+
+// RegTransform registers a named transform, a field tagged with attribute
+// `transform=name` is transformed by it before written, ex:
+// `glue:"Email,transform=lower"`. A transform is a function that takes and
+// outputs the type of the destination field, like `strings.ToLower`.
+func RegTransform(name string, transform interface{}) error {
+	vfunc := reflect.ValueOf(transform)
+	if vfunc.Kind() != reflect.Func {
+		return ErrNotFunction
+	}
+	ft := vfunc.Type()
+	if ft.NumIn() != 1 || ft.NumOut() != 1 || ft.In(0) != ft.Out(0) ||
+		ft.IsVariadic() {
+		return ErrIncompatSignature
+	}
+
+	transformLock.Lock()
+	defer transformLock.Unlock()
+	transforms[name] = vfunc
+	resetPlans()
+	return nil
+}
+
+// MustRegTransform is the shorthand of `RegTransform` on initialize, it panics
+// if the transform is not valid.
+func MustRegTransform(name string, transform interface{}) bool {
+	err := RegTransform(name, transform)
+	if err != nil {
+		panic(err)
+	}
+	return true
+}
+
+// DeregTransform deregisters the named transform.
+func DeregTransform(name string) {
+	transformLock.Lock()
+	defer transformLock.Unlock()
+	delete(transforms, name)
+	resetPlans()
+}
+
+// resolveTransform looks up the transform named `name` for a field of
+// `dstType`.
+func (fp *fieldPlan) resolveTransform(name string, dstType reflect.Type) {
+	transformLock.RLock()
+	vfunc, exist := transforms[name]
+	transformLock.RUnlock()
+	switch {
+	case !exist:
+		fp.Err = fmt.Errorf("%w: %q of field %#v", ErrUnknownTransform, name, fp.Name)
+	case vfunc.Type().In(0) != dstType:
+		fp.Err = fmt.Errorf(
+			"%w: transform %q does not take %v of field %#v",
+			ErrIncompatSignature, name, dstType, fp.Name,
+		)
+	default:
+		fp.Transform = vfunc
+	}
+}